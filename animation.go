@@ -0,0 +1,52 @@
+package cornice
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	submitAnimFrames   = 4
+	submitAnimInterval = 120 * time.Millisecond
+)
+
+var successStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("#00C853"))
+
+// animFrameMsg advances the submit animation by one frame.
+type animFrameMsg struct{}
+
+func (m model) nextAnimFrame() tea.Cmd {
+	return m.tick(submitAnimInterval, func(time.Time) tea.Msg {
+		return animFrameMsg{}
+	})
+}
+
+// updateAnimating handles messages while the submit animation plays. Input
+// is ignored except Ctrl+C; the form completes after the last frame.
+func (m model) updateAnimating(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case animFrameMsg:
+		m.animFrame++
+		if m.animFrame >= submitAnimFrames {
+			m.animating = false
+			return m.complete()
+		}
+		return m, m.nextAnimFrame()
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+	case tea.WindowSizeMsg:
+		m = m.resize(msg)
+	}
+	return m, nil
+}
+
+// flashing reports whether the borders are lit on the current frame.
+func (m model) flashing() bool {
+	return m.animating && m.animFrame%2 == 0
+}
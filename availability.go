@@ -0,0 +1,103 @@
+package cornice
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxSuggestionTries bounds how many numbered variants suggestUsername
+// tries before giving up.
+const maxSuggestionTries = 1000
+
+// availabilityMsg reports whether username is taken, with a free
+// alternative when it is.
+type availabilityMsg struct {
+	username   string
+	taken      bool
+	suggestion string
+}
+
+// suggestUsername returns the first of base1, base2, ... that is not
+// taken, or "" if none is found.
+func suggestUsername(base string, taken func(string) bool) string {
+	for i := 1; i <= maxSuggestionTries; i++ {
+		candidate := fmt.Sprintf("%s%d", base, i)
+		if !taken(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// checkAvailability looks up username in the background.
+func checkAvailability(username string, taken func(string) bool) tea.Cmd {
+	return func() tea.Msg {
+		msg := availabilityMsg{username: username, taken: taken(username)}
+		if msg.taken {
+			msg.suggestion = suggestUsername(username, taken)
+		}
+		return msg
+	}
+}
+
+// availabilityDelay is how long the username must stay unchanged before
+// it is looked up, so a check isn't started for every keystroke.
+const availabilityDelay = 300 * time.Millisecond
+
+// availabilityDueMsg fires availabilityDelay after username was typed.
+type availabilityDueMsg struct {
+	username string
+}
+
+// usernameChanged records the edit and, when an availability check is
+// configured, schedules one for once the username has settled.
+func (m model) usernameChanged() (model, tea.Cmd) {
+	m.usernameEditedAt = m.now()
+	if m.usernameTaken == nil || m.typedUsername() == "" {
+		return m, nil
+	}
+	username := m.typedUsername()
+	return m, m.tick(availabilityDelay, func(time.Time) tea.Msg {
+		return availabilityDueMsg{username: username}
+	})
+}
+
+// handleAvailabilityDue starts the check scheduled by usernameChanged,
+// unless the username has been edited since.
+func (m model) handleAvailabilityDue(msg availabilityDueMsg) tea.Cmd {
+	if msg.username != m.typedUsername() || m.now().Sub(m.usernameEditedAt) < availabilityDelay {
+		return nil
+	}
+	return checkAvailability(msg.username, m.usernameTaken)
+}
+
+// handleAvailability records the result of a check, flagging a taken
+// username and offering the
+// suggestion as ghost text accepted with Ctrl+G. Results for a username
+// that has since been edited are dropped.
+func (m model) handleAvailability(msg availabilityMsg) model {
+	if msg.username != m.typedUsername() {
+		return m
+	}
+	m.availability = msg
+	if msg.taken {
+		m.setErr(fieldUsername, "Username is taken")
+	}
+	if msg.suggestion != "" {
+		m.usernameInput.SetSuggestions([]string{msg.suggestion})
+	} else {
+		m.usernameInput.SetSuggestions(nil)
+	}
+	return m
+}
+
+// isTaken reports whether the current username is known to be taken.
+func (m model) isTaken() bool {
+	return m.availability.taken && m.availability.username == m.typedUsername()
+}
+
+// acceptSuggestionKey fills the username with the suggested alternative.
+var acceptSuggestionKey = key.NewBinding(key.WithKeys("ctrl+g"))
@@ -0,0 +1,51 @@
+package cornice
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAvailabilityCheckIsDebounced(t *testing.T) {
+	lookups := 0
+	taken := func(u string) bool {
+		lookups++
+		return u == "alice"
+	}
+	clock := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := typeInto(initialModel(WithUsernameTaken(taken)), fieldUsername, "alice", 50*time.Millisecond, clock)
+
+	// The ticks for the keystrokes before the last one are stale.
+	if cmd := m.handleAvailabilityDue(availabilityDueMsg{username: "ali"}); cmd != nil {
+		t.Error("a check was started for a username that has since changed")
+	}
+	// Straight after the last keystroke the username hasn't settled yet.
+	if cmd := m.handleAvailabilityDue(availabilityDueMsg{username: "alice"}); cmd != nil {
+		t.Error("a check was started before the username settled")
+	}
+	if lookups != 0 {
+		t.Fatalf("%d lookups while typing, want none", lookups)
+	}
+
+	clock.Advance(availabilityDelay)
+	cmd := m.handleAvailabilityDue(availabilityDueMsg{username: "alice"})
+	if cmd == nil {
+		t.Fatal("no check once the username settled")
+	}
+	m = drive(m, cmd())
+	if !m.isTaken() {
+		t.Error("alice not reported as taken")
+	}
+	if got := m.err(fieldUsername); got != "Username is taken" {
+		t.Errorf("username error = %q", got)
+	}
+}
+
+func TestSuggestUsername(t *testing.T) {
+	taken := func(u string) bool { return u == "bob" || u == "bob1" }
+	if got := suggestUsername("bob", taken); got != "bob2" {
+		t.Errorf("suggestUsername = %q, want %q", got, "bob2")
+	}
+	if got := suggestUsername("bob", func(string) bool { return true }); got != "" {
+		t.Errorf("suggestUsername with everything taken = %q, want none", got)
+	}
+}
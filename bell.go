@@ -0,0 +1,56 @@
+package cornice
+
+import (
+	"io"
+	"log"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// errorFlashTime is how long the offending field's border stays lit after
+// a blocked submit.
+const errorFlashTime = 300 * time.Millisecond
+
+// errorFlashEndMsg clears the error flash started with the same seq. Older
+// ticks from earlier blocked submits are ignored.
+type errorFlashEndMsg struct{ seq int }
+
+// blockedSubmit gives the configured cues that a submit was refused: a
+// terminal bell and a brief flash of the border of the field at index i.
+func (m model) blockedSubmit(i int) (model, tea.Cmd) {
+	var cmds []tea.Cmd
+	if m.bell {
+		out := m.terminalOutput()
+		cmds = append(cmds, func() tea.Msg {
+			if _, err := io.WriteString(out, "\a"); err != nil {
+				log.Printf("bell: %v", err)
+			}
+			return nil
+		})
+	}
+	if m.errorFlash {
+		m.flashSeq++
+		m.flashField = i
+		m.errorFlashing = true
+		seq := m.flashSeq
+		cmds = append(cmds, m.tick(errorFlashTime, func(time.Time) tea.Msg {
+			return errorFlashEndMsg{seq: seq}
+		}))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// endErrorFlash turns the flash off if msg belongs to the latest one.
+func (m model) endErrorFlash(msg errorFlashEndMsg) model {
+	if msg.seq == m.flashSeq {
+		m.errorFlashing = false
+	}
+	return m
+}
+
+// errorFlashed reports whether the border of the field at index i is lit
+// by a blocked submit.
+func (m model) errorFlashed(i int) bool {
+	return m.errorFlashing && i == m.flashField
+}
@@ -0,0 +1,45 @@
+package cornice
+
+import (
+	"bytes"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestBlockedSubmitFlashes(t *testing.T) {
+	m := initialModel(WithErrorFlash(), WithUsernameLength(3, 0))
+	m = drive(m, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.done || !m.errorFlashed(0) {
+		t.Fatalf("done = %v, flashed = %v after a blocked submit; want the username lit", m.done, m.errorFlashed(0))
+	}
+	if m.errorFlashed(1) {
+		t.Error("the valid password is lit too")
+	}
+
+	stale := errorFlashEndMsg{seq: m.flashSeq}
+	m = drive(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m = drive(m, stale)
+	if !m.errorFlashed(0) {
+		t.Error("the tick of an earlier flash ended the latest one")
+	}
+	m = drive(m, errorFlashEndMsg{seq: m.flashSeq})
+	if m.errorFlashed(0) {
+		t.Error("the tick didn't clear the flash")
+	}
+}
+
+func TestBlockedSubmitBell(t *testing.T) {
+	var out bytes.Buffer
+	m := initialModel(WithBell(), WithOutput(&out), WithUsernameLength(3, 0))
+	drive(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if got := out.String(); got != "\a" {
+		t.Errorf("output = %q after a blocked submit, want a bell", got)
+	}
+
+	out.Reset()
+	drive(initialModel(WithOutput(&out), WithUsernameLength(3, 0)), tea.KeyMsg{Type: tea.KeyEnter})
+	if out.Len() != 0 {
+		t.Errorf("output = %q without WithBell, want nothing", out.String())
+	}
+}
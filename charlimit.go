@@ -0,0 +1,21 @@
+package cornice
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// charCounter renders "n/limit" for f when it has a character limit, red
+// once the limit is reached, or "" otherwise.
+func (m model) charCounter(f field) string {
+	in := m.input(f)
+	if in == nil || in.CharLimit <= 0 || (f != fieldUsername && f != fieldPassword) {
+		return ""
+	}
+	n := utf8.RuneCountInString(in.Value())
+	counter := fmt.Sprintf("%d/%d", n, in.CharLimit)
+	if n >= in.CharLimit {
+		return m.style(errorStyle).Render(counter)
+	}
+	return m.style(statusStyle).Render(counter)
+}
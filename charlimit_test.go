@@ -0,0 +1,49 @@
+package cornice
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
+)
+
+func TestCharLimitRejectsExtraInput(t *testing.T) {
+	m := initialModel(WithCharLimits(5, 3))
+	m = drive(m, keys("alice!")...)
+	if v := m.usernameInput.Value(); v != "alice" {
+		t.Errorf("username = %q, want typing stopped at 5 characters", v)
+	}
+	m = drive(m, tea.KeyMsg{Type: tea.KeyTab}, paste("secret"))
+	if v := m.passwordInput.Value(); v != "sec" {
+		t.Errorf("password = %q, want a paste cut at 3 characters", v)
+	}
+}
+
+func TestCharCounter(t *testing.T) {
+	m := initialModel(WithCharLimits(20, 0), WithColorMode(ColorNever))
+	m = drive(m, tea.WindowSizeMsg{Width: 80, Height: 24})
+	m = drive(m, keys("héllo")...)
+	if got := m.charCounter(fieldUsername); got != "5/20" {
+		t.Errorf("username counter = %q, want 5/20 counting runes", got)
+	}
+	if !strings.Contains(m.View(), "5/20") {
+		t.Error("the view lacks the counter")
+	}
+	if got := m.charCounter(fieldPassword); got != "" {
+		t.Errorf("password counter = %q without a limit, want none", got)
+	}
+}
+
+func TestCharCounterRedAtLimit(t *testing.T) {
+	withColorProfile(t, termenv.TrueColor)
+	m := initialModel(WithCharLimits(3, 0))
+	m = drive(m, keys("ab")...)
+	if got := m.charCounter(fieldUsername); got != m.style(statusStyle).Render("2/3") {
+		t.Errorf("counter below the limit = %q, want the status style", got)
+	}
+	m = drive(m, keys("c")...)
+	if got := m.charCounter(fieldUsername); got != m.style(errorStyle).Render("3/3") {
+		t.Errorf("counter at the limit = %q, want the error style", got)
+	}
+}
@@ -0,0 +1,24 @@
+package cornice
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pasteKey inserts the system clipboard into the focused field.
+const pasteKey = "ctrl+v"
+
+// clipboardPaste reads the clipboard and returns it as a pasted keystroke
+// for the focused field, so it goes through the same filtering as typed
+// input. When the clipboard can't be read the field shows an error and
+// ok is false.
+func (m model) clipboardPaste() (_ model, _ tea.KeyMsg, ok bool) {
+	text, err := m.readClipboard()
+	if err != nil {
+		m.setErr(m.focusedField(), "Couldn't read the clipboard")
+		return m, tea.KeyMsg{}, false
+	}
+	text = strings.TrimRight(text, "\r\n")
+	return m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text), Paste: true}, true
+}
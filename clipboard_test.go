@@ -0,0 +1,46 @@
+package cornice
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var ctrlV = tea.KeyMsg{Type: tea.KeyCtrlV}
+
+func TestClipboardPasteIntoFocusedField(t *testing.T) {
+	m := initialModel()
+	m.readClipboard = func() (string, error) { return "s3cret!\n", nil }
+	m = drive(m, script(keys("alice"), []tea.Msg{tea.KeyMsg{Type: tea.KeyTab}, ctrlV})...)
+	if v := m.passwordInput.Value(); v != "s3cret!" {
+		t.Errorf("password = %q, want the clipboard without its newline", v)
+	}
+	if v := m.usernameInput.Value(); v != "alice" {
+		t.Errorf("username = %q, want it untouched", v)
+	}
+	if !m.pasted {
+		t.Error("a clipboard paste isn't marked as pasted")
+	}
+}
+
+func TestClipboardPasteAtCursor(t *testing.T) {
+	m := initialModel()
+	m.readClipboard = func() (string, error) { return "ic", nil }
+	m = drive(m, script(keys("ale"), []tea.Msg{tea.KeyMsg{Type: tea.KeyLeft}, ctrlV})...)
+	if v := m.usernameInput.Value(); v != "alice" {
+		t.Errorf("username = %q, want the clipboard inserted at the cursor", v)
+	}
+}
+
+func TestClipboardFailureShowsError(t *testing.T) {
+	m := initialModel()
+	m.readClipboard = func() (string, error) { return "", errors.New("no display") }
+	m = drive(m, script(keys("al"), []tea.Msg{ctrlV})...)
+	if err := m.err(fieldUsername); err != "Couldn't read the clipboard" {
+		t.Errorf("error = %q, want the clipboard failure shown", err)
+	}
+	if v := m.usernameInput.Value(); v != "al" {
+		t.Errorf("username = %q, want it kept", v)
+	}
+}
@@ -0,0 +1,394 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/dyne/cornice"
+)
+
+func main() {
+	stdin := flag.Bool("stdin", false, "read the username and password as two lines from stdin, and the one-time code as a third with -totp, instead of showing the form")
+	control := flag.Bool("control", false, "read newline-delimited JSON commands from stdin")
+	title := flag.String("title", "", "header shown above the fields")
+	maxFields := flag.Int("max-fields", 0, "maximum number of fields shown before the form scrolls (0 fits the terminal)")
+	maxFormWidth := flag.Int("max-form-width", 0, "cap the form width, borders included (0 for the default 52 columns)")
+	maxFormHeight := flag.Int("max-form-height", 0, "cap the form height (0 fits the terminal)")
+	align := flag.String("align", "center", "horizontal placement of the form: left, center or right")
+	valign := flag.String("valign", "center", "vertical placement of the form: top, center or bottom")
+	totp := flag.Bool("totp", false, "ask for a 6-digit one-time code after the password")
+	email := flag.Bool("email", false, "ask for an email address instead of a username")
+	usernameChars := flag.String("username-chars", "", "characters allowed in the username (empty allows any)")
+	highlightInvalid := flag.Bool("highlight-invalid", false, "highlight disallowed username characters instead of dropping them")
+	usernamePattern := flag.String("username-pattern", "", "regular expression the username must match, e.g. ^[a-z0-9_]{3,20}$")
+	usernameMin := flag.Int("username-min", 0, "minimum username length")
+	usernameMax := flag.Int("username-max", 0, "maximum username length (0 for no limit)")
+	passwordMin := flag.Int("password-min", 0, "minimum password length")
+	passwordMax := flag.Int("password-max", 0, "maximum password length (0 for no limit)")
+	generate := flag.Int("generate", 0, "let Ctrl+G on the password fill in a random password of this length (0 disables)")
+	usernameLimit := flag.Int("username-limit", 0, "stop the username at this many characters, with a counter (0 for no limit)")
+	passwordLimit := flag.Int("password-limit", 0, "stop the password at this many characters, with a counter (0 for no limit)")
+	strengthMeter := flag.Bool("strength-meter", false, "show the password strength as it is typed")
+	validationBorders := flag.Bool("validation-borders", false, "color each border red or green as its value fails or passes validation")
+	bell := flag.Bool("bell", false, "ring the terminal bell when a submit is blocked by validation")
+	errorFlash := flag.Bool("error-flash", false, "briefly flash the border of the first invalid field when a submit is blocked")
+	validateOnBlur := flag.Bool("validate-on-blur", false, "validate each field as soon as it loses focus")
+	placement := flag.String("error-placement", "below", "where validation errors are shown: below, above or footer")
+	transform := flag.String("username-transform", "", "transform applied to the username on submit: lower, upper or trim")
+	register := flag.Bool("register", false, "add a confirm password field that must match the password")
+	guestKey := flag.String("guest-key", "", "key offering guest access, e.g. ctrl+o (empty disables it)")
+	netrcHost := flag.String("netrc", "", "prefill the username from the .netrc entry for this host")
+	netrcPassword := flag.Bool("netrc-password", false, "also prefill the password from .netrc")
+	forgotURL := flag.String("forgot-url", "", "add a forgot password action that opens `url` in the browser")
+	persistReveal := flag.Bool("persist-reveal", true, "remember whether the password is revealed (Ctrl+R) for the next launch")
+	rememberMe := flag.Bool("remember-me", false, "offer to remember the username for the next launch")
+	confirmEmpty := flag.Bool("confirm-empty", false, "ask for confirmation before submitting with blank fields")
+	review := flag.Bool("review", false, "show a summary to confirm before submitting")
+	summary := flag.String("password-summary", "hidden", "how the result describes the password: hidden, length, strength or none")
+	jsonOutput := flag.Bool("json", false, "print the credentials as JSON on stdout instead of showing the result")
+	fd := flag.Int("fd", -1, "write the username and password, one per line, to this file descriptor instead of showing the result")
+	quitOnSubmit := flag.Bool("quit-on-submit", false, "exit as soon as the form is submitted")
+	animate := flag.Bool("animate", false, "flash the form on a successful submit")
+	dismissOnKey := flag.Bool("dismiss-on-key", false, "exit on any key once the result is shown")
+	theme := flag.String("theme", "auto", "color theme: auto, default, dark, light or high-contrast")
+	color := flag.String("color", "auto", "use color: auto, always or never")
+	focusMarker := flag.String("focus-marker", "", "single-column glyph drawn at the focused box's top-left corner, e.g. ▸")
+	focusedTextColor := flag.String("focused-text-color", "", "text color of the focused input")
+	blurredTextColor := flag.String("blurred-text-color", "", "text color of the blurred inputs")
+	forceMask := flag.Bool("force-mask", false, "start with the password masked regardless of the saved preference")
+	metrics := flag.Bool("metrics", false, "log the time taken to log in to stderr")
+	fromFirstKey := flag.Bool("metrics-from-first-key", false, "measure the time to log in from the first keystroke rather than program start")
+	echoChar := flag.String("echo-char", "*", "character masking the password")
+	revealTyped := flag.Duration("reveal-typed", 0, "show each character typed into a masked field for this long, e.g. 500ms")
+	revealLast := flag.Int("reveal-last", 0, "keep the last N characters of the password visible while masked")
+	maskAfter := flag.Duration("mask-after", 0, "mask a revealed password again after this much inactivity (0 disables)")
+	idleTimeout := flag.Duration("idle-timeout", 0, "close the form after this much inactivity (0 disables)")
+	maskOnBlur := flag.Bool("mask-on-blur", false, "mask the password and dim the form while the terminal is unfocused")
+	inline := flag.Bool("inline", false, "render the form below the existing output without clearing the screen (excludes -no-clear)")
+	noClear := flag.Bool("no-clear", false, "use the alternate screen instead of clearing, restoring the terminal on exit (excludes -inline)")
+	literalTab := flag.Bool("literal-tab", false, "make Tab insert a tab into the field; Down and Up move between fields")
+	enterAdvances := flag.Bool("enter-advances", false, "make Enter move to the next field and submit with Ctrl+S or Ctrl+D")
+	initialFocus := flag.Int("focus", -1, "index of the field to start on, in focus order; by default the first empty one")
+	vimKeys := flag.Bool("vim-keys", false, "move between fields with j and k while the focused field is empty")
+	noMouse := flag.Bool("no-mouse", false, "start with mouse handling disabled (toggle with Ctrl+T)")
+	noHelp := flag.Bool("no-help", false, "hide the key help beneath the form")
+	statusBar := flag.Bool("status-bar", false, "show a status line with the validity of each field and the one being edited")
+	schema := flag.Bool("schema", false, "print the form's JSON schema and exit")
+	eventLog := flag.String("event-log", "", "append a line per key, mouse and resize event to this file")
+	debug := flag.Bool("debug", false, "log diagnostic messages to stderr")
+	preview := flag.String("preview", "", "print the first frame for a `WxH` terminal and exit")
+	prewarmSize := flag.Bool("prewarm-size", false, "probe the terminal size before starting instead of waiting for the first resize event")
+
+	// The config file supplies defaults; flags on the command line
+	// override them.
+	cfg, err := cornice.LoadConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "config:", err)
+		os.Exit(2)
+	}
+	if err := applyConfig(flag.CommandLine, cfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	flag.Parse()
+
+	if *inline && *noClear {
+		fmt.Fprintln(os.Stderr, "-inline and -no-clear are mutually exclusive")
+		os.Exit(2)
+	}
+
+	errorPlacement, err := cornice.ParseErrorPlacement(*placement)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	passwordSummary, err := cornice.ParsePasswordSummary(*summary)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	usernameTransform, err := cornice.ParseTransform(*transform)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	alignH, err := cornice.ParseHorizontalPosition(*align)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	alignV, err := cornice.ParseVerticalPosition(*valign)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	colorMode, err := cornice.ParseColorMode(*color)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	formTheme, err := cornice.ParseTheme(*theme)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if *focusMarker != "" {
+		formTheme.FocusMarker = *focusMarker
+	}
+
+	echo := []rune(*echoChar)
+	if len(echo) != 1 {
+		fmt.Fprintf(os.Stderr, "-echo-char must be a single character, got %q\n", *echoChar)
+		os.Exit(2)
+	}
+
+	var usernameRE *regexp.Regexp
+	if *usernamePattern != "" {
+		usernameRE, err = regexp.Compile(*usernamePattern)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+
+	opts := []cornice.Option{cornice.WithTheme(formTheme)}
+	if !isSet(flag.CommandLine, "theme") {
+		// -theme already defaults to the config's theme; the colors
+		// customize it, but not one picked on the command line. The size
+		// caps are flag defaults too, applied below.
+		cfg.Theme = ""
+		opts = append(opts, cornice.WithConfig(cfg))
+	}
+	opts = append(opts,
+		cornice.WithTitle(*title),
+		cornice.WithMaxFields(*maxFields),
+		cornice.WithMaxFormSize(*maxFormWidth, *maxFormHeight),
+		cornice.WithAlignment(alignH, alignV),
+		cornice.WithUsernameChars(*usernameChars, *highlightInvalid),
+		cornice.WithUsernameTransform(usernameTransform),
+		cornice.WithUsernamePattern(usernameRE),
+		cornice.WithUsernameLength(*usernameMin, *usernameMax),
+		cornice.WithPasswordLength(*passwordMin, *passwordMax),
+		cornice.WithCharLimits(*usernameLimit, *passwordLimit),
+		cornice.WithErrorPlacement(errorPlacement),
+		cornice.WithPasswordSummary(passwordSummary),
+		cornice.WithColorMode(colorMode),
+		cornice.WithTextColors(*focusedTextColor, *blurredTextColor),
+		cornice.WithMaskAfter(*maskAfter),
+		cornice.WithIdleTimeout(*idleTimeout),
+		cornice.WithEchoCharacter(echo[0]),
+		cornice.WithRevealLast(*revealLast),
+		cornice.WithMouse(!*noMouse),
+		cornice.WithHelp(!*noHelp),
+	)
+	if *revealTyped > 0 {
+		opts = append(opts, cornice.WithLastCharReveal(*revealTyped))
+	}
+	if *stdin {
+		opts = append(opts, cornice.WithNonInteractive())
+	}
+	if *control {
+		opts = append(opts, cornice.WithControl())
+	}
+	if *totp {
+		opts = append(opts, cornice.WithTOTP())
+	}
+	if *email {
+		opts = append(opts, cornice.WithEmail())
+	}
+	if *generate > 0 {
+		opts = append(opts, cornice.WithPasswordGenerator(*generate))
+	}
+	if *strengthMeter {
+		opts = append(opts, cornice.WithStrengthMeter())
+	}
+	if *literalTab {
+		opts = append(opts, cornice.WithLiteralTab())
+	}
+	if *enterAdvances {
+		opts = append(opts, cornice.WithEnterAdvances())
+	}
+	if *initialFocus >= 0 {
+		opts = append(opts, cornice.WithInitialFocus(*initialFocus))
+	}
+	if *vimKeys {
+		opts = append(opts, cornice.WithVimKeys())
+	}
+	if *validationBorders {
+		opts = append(opts, cornice.WithValidationBorders())
+	}
+	if *bell {
+		opts = append(opts, cornice.WithBell())
+	}
+	if *errorFlash {
+		opts = append(opts, cornice.WithErrorFlash())
+	}
+	if *validateOnBlur {
+		opts = append(opts, cornice.WithValidateOnBlur())
+	}
+	if *register {
+		opts = append(opts, cornice.WithRegister())
+	}
+	if *guestKey != "" {
+		opts = append(opts, cornice.WithGuest(*guestKey))
+	}
+	if *netrcHost != "" {
+		opts = append(opts, cornice.WithNetrc(*netrcHost, *netrcPassword))
+	}
+	if *forgotURL != "" {
+		opts = append(opts, cornice.WithForgotPassword(cornice.OpenURL(*forgotURL)))
+	}
+	if *persistReveal {
+		opts = append(opts, cornice.WithPersistentReveal())
+	}
+	if *rememberMe {
+		opts = append(opts, cornice.WithRememberMe())
+	}
+	if *confirmEmpty {
+		opts = append(opts, cornice.WithConfirmEmpty())
+	}
+	if *review {
+		opts = append(opts, cornice.WithReview())
+	}
+	if *jsonOutput {
+		opts = append(opts, cornice.WithJSONOutput())
+	}
+	if *fd >= 0 {
+		f, err := openFD(*fd)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		defer f.Close()
+		opts = append(opts, cornice.WithCredentialsOutput(f))
+	}
+	if *quitOnSubmit {
+		opts = append(opts, cornice.WithQuitOnSubmit())
+	}
+	if *animate {
+		opts = append(opts, cornice.WithSubmitAnimation())
+	}
+	if *dismissOnKey {
+		opts = append(opts, cornice.WithDismissOnKey())
+	}
+	if *forceMask {
+		opts = append(opts, cornice.WithForceMask())
+	}
+	if *metrics {
+		opts = append(opts, cornice.WithMetrics(*fromFirstKey))
+	}
+	if *maskOnBlur {
+		opts = append(opts, cornice.WithMaskOnTerminalBlur())
+	}
+	if *inline {
+		opts = append(opts, cornice.WithInline())
+	}
+	if *noClear {
+		opts = append(opts, cornice.WithAltScreen())
+	}
+	if *statusBar {
+		opts = append(opts, cornice.WithStatusBar())
+	}
+	if *eventLog != "" {
+		f, err := os.OpenFile(*eventLog, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		opts = append(opts, cornice.WithLogger(f))
+	}
+	if *debug {
+		opts = append(opts, cornice.WithDebug())
+	}
+	if *prewarmSize {
+		opts = append(opts, cornice.WithPrewarmedSize())
+	}
+
+	if *preview != "" {
+		width, height, err := cornice.ParseSize(*preview)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		fmt.Println(cornice.Preview(width, height, opts...))
+		return
+	}
+
+	if *schema {
+		data, err := cornice.Schema(opts...)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	_, err = cornice.Run(opts...)
+	if err != nil && !errors.Is(err, cornice.ErrCancelled) && !errors.Is(err, cornice.ErrTimeout) {
+		fmt.Fprintln(os.Stderr, "Error running program:", err)
+		os.Exit(1)
+	}
+}
+
+// applyConfig makes the config's settings the defaults of the matching
+// flags in fs. They don't count as set on the command line.
+func applyConfig(fs *flag.FlagSet, cfg cornice.Config) error {
+	defaults := map[string]string{}
+	if cfg.Theme != "" {
+		defaults["theme"] = cfg.Theme
+	}
+	if cfg.MaxFormWidth > 0 {
+		defaults["max-form-width"] = strconv.Itoa(cfg.MaxFormWidth)
+	}
+	if cfg.MaxFormHeight > 0 {
+		defaults["max-form-height"] = strconv.Itoa(cfg.MaxFormHeight)
+	}
+	for name, value := range cfg.Flags {
+		defaults[name] = value
+	}
+	for name, value := range defaults {
+		f := fs.Lookup(name)
+		if f == nil {
+			return fmt.Errorf("config: no such flag -%s", name)
+		}
+		if err := f.Value.Set(value); err != nil {
+			return fmt.Errorf("config: flag %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// isSet reports whether the flag name was given on the command line.
+func isSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// openFD returns the already open file descriptor fd. os.NewFile accepts any
+// number, so the descriptor is checked with Stat before it is written to.
+func openFD(fd int) (*os.File, error) {
+	f := os.NewFile(uintptr(fd), "fd"+strconv.Itoa(fd))
+	if f == nil {
+		return nil, fmt.Errorf("-fd %d: invalid file descriptor", fd)
+	}
+	if _, err := f.Stat(); err != nil {
+		return nil, fmt.Errorf("-fd %d: not an open file descriptor", fd)
+	}
+	return f, nil
+}
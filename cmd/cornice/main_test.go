@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/dyne/cornice"
+)
+
+// newFlags returns a flag set with the flags a config may set.
+func newFlags() (*flag.FlagSet, *string, *int) {
+	fs := flag.NewFlagSet("cornice", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	theme := fs.String("theme", "auto", "")
+	width := fs.Int("max-form-width", 0, "")
+	return fs, theme, width
+}
+
+func TestApplyConfigSetsDefaults(t *testing.T) {
+	fs, theme, width := newFlags()
+	if err := applyConfig(fs, cornice.Config{Theme: "dark", MaxFormWidth: 60}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	if *theme != "dark" || *width != 60 {
+		t.Errorf("theme = %q, width = %d; want the config's dark and 60", *theme, *width)
+	}
+	if isSet(fs, "theme") {
+		t.Error("a config default counts as set on the command line")
+	}
+}
+
+func TestCommandLineOverridesConfig(t *testing.T) {
+	fs, theme, width := newFlags()
+	if err := applyConfig(fs, cornice.Config{Theme: "dark", MaxFormWidth: 60}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse([]string{"-theme", "light"}); err != nil {
+		t.Fatal(err)
+	}
+	if *theme != "light" || *width != 60 {
+		t.Errorf("theme = %q, width = %d; want light from the command line and 60", *theme, *width)
+	}
+	if !isSet(fs, "theme") {
+		t.Error("-theme given on the command line isn't reported as set")
+	}
+}
+
+func TestApplyConfigUnknownFlag(t *testing.T) {
+	fs, _, _ := newFlags()
+	if err := applyConfig(fs, cornice.Config{Flags: map[string]string{"no-such-flag": "1"}}); err == nil {
+		t.Error("applyConfig accepted an unknown flag")
+	}
+}
+
+func TestOpenFDRejectsClosedDescriptor(t *testing.T) {
+	if _, err := openFD(987654); err == nil {
+		t.Error("openFD accepted a descriptor that isn't open")
+	}
+}
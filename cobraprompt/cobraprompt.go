@@ -0,0 +1,28 @@
+// Package cobraprompt adapts the Cornice form into a credential prompt for
+// cobra commands.
+package cobraprompt
+
+import (
+	"github.com/dyne/cornice"
+	"github.com/spf13/cobra"
+)
+
+// PromptFunc asks for credentials on behalf of a command.
+type PromptFunc func(cmd *cobra.Command) (user, pass string, err error)
+
+// Prompt shows the form on cmd's input and output streams and returns the
+// entered credentials.
+func Prompt(cmd *cobra.Command) (user, pass string, err error) {
+	return New()(cmd)
+}
+
+// New returns a PromptFunc that shows the form configured with opts.
+func New(opts ...cornice.Option) PromptFunc {
+	return func(cmd *cobra.Command) (string, string, error) {
+		all := append([]cornice.Option{
+			cornice.WithInput(cmd.InOrStdin()),
+			cornice.WithOutput(cmd.OutOrStdout()),
+		}, opts...)
+		return cornice.Prompt(all...)
+	}
+}
@@ -0,0 +1,114 @@
+package cornice
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// ColorMode selects whether the form is drawn in color.
+type ColorMode int
+
+const (
+	// ColorAuto uses color when the terminal supports it and NO_COLOR is
+	// not set.
+	ColorAuto ColorMode = iota
+	// ColorAlways uses color even when the output isn't a terminal.
+	ColorAlways
+	// ColorNever draws without color; focus is shown by the border shape.
+	ColorNever
+)
+
+// ParseColorMode parses "auto", "always" or "never".
+func ParseColorMode(s string) (ColorMode, error) {
+	switch s {
+	case "auto":
+		return ColorAuto, nil
+	case "always":
+		return ColorAlways, nil
+	case "never":
+		return ColorNever, nil
+	}
+	return ColorAuto, fmt.Errorf("unknown color mode %q", s)
+}
+
+// colorProfile picks the profile to render with under mode, given the
+// one detected from the terminal and whether NO_COLOR is set.
+func colorProfile(mode ColorMode, detected termenv.Profile, noColor bool) termenv.Profile {
+	switch mode {
+	case ColorNever:
+		return termenv.Ascii
+	case ColorAlways:
+		if detected == termenv.Ascii {
+			return termenv.ANSI256
+		}
+		return detected
+	}
+	if noColor {
+		return termenv.Ascii
+	}
+	return detected
+}
+
+// detectColorProfile reports the color profile of the terminal r writes
+// to; tests may replace it.
+var detectColorProfile = (*lipgloss.Renderer).ColorProfile
+
+// newRenderer returns the renderer a form draws with on w under mode. Each
+// form gets its own, so the profile mode picks never leaks into the global
+// lipgloss renderer used by the rest of the program.
+func newRenderer(w io.Writer, mode ColorMode) *lipgloss.Renderer {
+	r := lipgloss.NewRenderer(w)
+	r.SetColorProfile(colorProfile(mode, detectColorProfile(r), noColorSet()))
+	return r
+}
+
+// bindRenderer builds the form's renderer on its output and binds the
+// theme, key help and spinner to it. Text colors given with WithTextColors
+// are laid over the resolved theme.
+func (m model) bindRenderer() model {
+	out := io.Writer(os.Stdout)
+	if m.run.output != nil {
+		out = m.run.output
+	}
+	m.renderer = newRenderer(out, m.run.colorMode)
+	m.theme = m.theme.bind(m.renderer)
+	m.focusedText = m.theme.FocusedText
+	if m.focusedColor != "" {
+		m.focusedText = m.focusedText.Foreground(lipgloss.Color(m.focusedColor))
+	}
+	m.blurredText = m.theme.BlurredText
+	if m.blurredColor != "" {
+		m.blurredText = m.blurredText.Foreground(lipgloss.Color(m.blurredColor))
+	}
+	hs := &m.help.Styles
+	for _, st := range []*lipgloss.Style{&hs.Ellipsis, &hs.ShortKey, &hs.ShortDesc, &hs.ShortSeparator, &hs.FullKey, &hs.FullDesc, &hs.FullSeparator} {
+		*st = m.style(*st)
+	}
+	m.spinner.Style = m.style(m.spinner.Style)
+	return m
+}
+
+// style binds s to the form's renderer.
+func (m model) style(s lipgloss.Style) lipgloss.Style {
+	return s.Renderer(m.renderer)
+}
+
+// noColorSet reports whether NO_COLOR asks for no color. An empty value
+// doesn't count, as no-color.org specifies.
+func noColorSet() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// AutoTheme returns a theme that is resolved when the form starts: the
+// light theme on terminals with a light background, and the default theme
+// otherwise, including when there is no color.
+func AutoTheme() Theme {
+	t := DefaultTheme()
+	light := themes["light"]()
+	t.light = &light
+	return t
+}
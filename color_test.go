@@ -0,0 +1,127 @@
+package cornice
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func TestColorProfile(t *testing.T) {
+	tests := []struct {
+		mode     ColorMode
+		detected termenv.Profile
+		noColor  bool
+		want     termenv.Profile
+	}{
+		{ColorAuto, termenv.TrueColor, false, termenv.TrueColor},
+		{ColorAuto, termenv.TrueColor, true, termenv.Ascii},
+		{ColorAuto, termenv.Ascii, false, termenv.Ascii},
+		{ColorAlways, termenv.TrueColor, true, termenv.TrueColor},
+		{ColorAlways, termenv.Ascii, false, termenv.ANSI256},
+		{ColorNever, termenv.TrueColor, false, termenv.Ascii},
+	}
+	for _, tt := range tests {
+		if got := colorProfile(tt.mode, tt.detected, tt.noColor); got != tt.want {
+			t.Errorf("colorProfile(%v, %v, NO_COLOR %v) = %v, want %v", tt.mode, tt.detected, tt.noColor, got, tt.want)
+		}
+	}
+}
+
+func TestNewRendererHonoursNoColor(t *testing.T) {
+	withColorProfile(t, termenv.TrueColor)
+
+	t.Setenv("NO_COLOR", "1")
+	if p := newRenderer(io.Discard, ColorAlways).ColorProfile(); p == termenv.Ascii {
+		t.Error("ColorAlways gave way to NO_COLOR")
+	}
+	if p := newRenderer(io.Discard, ColorAuto).ColorProfile(); p != termenv.Ascii {
+		t.Errorf("profile = %v with NO_COLOR set, want Ascii", p)
+	}
+}
+
+func TestColorModeLeavesGlobalProfile(t *testing.T) {
+	withColorProfile(t, termenv.TrueColor)
+	before := lipgloss.ColorProfile()
+
+	for _, mode := range []ColorMode{ColorAlways, ColorNever} {
+		Preview(80, 24, WithColorMode(mode))
+		m := initialModel(WithColorMode(mode), WithOutput(io.Discard))
+		if p := m.renderer.ColorProfile(); p != colorProfile(mode, termenv.TrueColor, false) {
+			t.Errorf("form profile = %v under mode %v", p, mode)
+		}
+	}
+	if p := lipgloss.ColorProfile(); p != before {
+		t.Errorf("global profile = %v after building forms, want %v", p, before)
+	}
+}
+
+func TestColorNeverDrawsWithoutColor(t *testing.T) {
+	withColorProfile(t, termenv.TrueColor)
+	if view := Preview(80, 24, WithColorMode(ColorNever)); strings.Contains(view, "\x1b[38;") {
+		t.Errorf("ColorNever preview contains color codes:\n%q", view)
+	}
+	if view := Preview(80, 24); !strings.Contains(view, "38;2;") {
+		t.Error("ColorAuto preview has no color on a true color terminal")
+	}
+}
+
+func TestAutoThemeFollowsBackground(t *testing.T) {
+	r := lipgloss.NewRenderer(io.Discard)
+	r.SetColorProfile(termenv.TrueColor)
+	auto := AutoTheme()
+	auto.FocusMarker = "▸"
+
+	r.SetHasDarkBackground(false)
+	light := auto.bind(r)
+	if got := light.Title.GetForeground(); got != themes["light"]().Title.GetForeground() {
+		t.Errorf("title color = %v on a light background, want the light theme's", got)
+	}
+	if light.FocusMarker != "▸" {
+		t.Errorf("focus marker = %q after resolving, want ▸", light.FocusMarker)
+	}
+
+	r.SetHasDarkBackground(true)
+	if got := auto.bind(r).Title.GetForeground(); got != DefaultTheme().Title.GetForeground() {
+		t.Errorf("title color = %v on a dark background, want the default theme's", got)
+	}
+
+	r.SetHasDarkBackground(false)
+	r.SetColorProfile(termenv.Ascii)
+	if got := auto.bind(r).Title.GetForeground(); got != DefaultTheme().Title.GetForeground() {
+		t.Errorf("title color = %v without color, want the default theme's", got)
+	}
+}
+
+func TestAutoThemeKeepsConfigColors(t *testing.T) {
+	r := lipgloss.NewRenderer(io.Discard)
+	r.SetColorProfile(termenv.TrueColor)
+	r.SetHasDarkBackground(false)
+	c := Config{}
+	c.Colors.Title = "#123456"
+	if got := c.ApplyColors(AutoTheme()).bind(r).Title.GetForeground(); got != lipgloss.Color("#123456") {
+		t.Errorf("title color = %v, want the configured #123456", got)
+	}
+}
+
+func TestNoColorSet(t *testing.T) {
+	for value, want := range map[string]bool{"1": true, "true": true, "": false} {
+		t.Setenv("NO_COLOR", value)
+		if got := noColorSet(); got != want {
+			t.Errorf("noColorSet() = %v with NO_COLOR=%q, want %v", got, value, want)
+		}
+	}
+}
+
+func TestParseColorMode(t *testing.T) {
+	for s, want := range map[string]ColorMode{"auto": ColorAuto, "always": ColorAlways, "never": ColorNever} {
+		if got, err := ParseColorMode(s); err != nil || got != want {
+			t.Errorf("ParseColorMode(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseColorMode("sometimes"); err == nil {
+		t.Error("ParseColorMode accepted an unknown mode")
+	}
+}
@@ -0,0 +1,65 @@
+package cornice
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const tooSmallHint = "Terminal too small"
+
+// compact reports whether the terminal is too short for even one bordered
+// box alongside everything else on screen, or too small for the form at
+// all, so the fields collapse to one line each.
+func (m model) compact() bool {
+	return (m.height > 0 && m.fieldRows() < m.fieldHeight()) || m.tooSmall()
+}
+
+// tooSmall reports whether the form can't be shown at all: there is less
+// than a row per field or the terminal is narrower than the smallest box.
+func (m model) tooSmall() bool {
+	return (m.height > 0 && m.availableHeight() < m.fieldCount()) ||
+		(m.width > 0 && m.width < m.bounds.minWidth+2)
+}
+
+// compactView renders each field on a single line, the focused one marked,
+// with nothing else around them.
+func (m model) compactView() string {
+	if m.tooSmall() {
+		return m.style(errorStyle).MaxWidth(m.width).Render(tooSmallHint)
+	}
+	width := m.boxWidth() + 2
+	lines := make([]string, 0, m.fieldCount())
+	for i, f := range m.fields() {
+		focused := i == m.focused && !m.submitting
+		marker := "  "
+		if focused {
+			marker = "> "
+		}
+		if f == fieldRemember {
+			lines = append(lines, marker+m.rememberView(focused))
+			continue
+		}
+		if f == fieldForgot {
+			lines = append(lines, marker+m.forgotView(focused))
+			continue
+		}
+		label := marker + m.theme.Title.Render(m.fieldTitle(f)) + " "
+		in := m.styledInput(*m.input(f), focused)
+		in.Prompt = ""
+		if m.fieldSecret(f) {
+			in.EchoMode = m.echoMode()
+		}
+		view := inputView(m.renderer, in, width-lipgloss.Width(label), nil, 0)
+		lines = append(lines, label+view)
+	}
+	return m.style(lipgloss.NewStyle()).MaxWidth(width).Render(strings.Join(lines, "\n"))
+}
+
+// compactFieldAt maps a row of the compact layout to its field.
+func (m model) compactFieldAt(x, y int) (int, bool) {
+	if m.tooSmall() || x < 0 || x >= m.boxWidth()+2 || y < 0 || y >= m.fieldCount() {
+		return 0, false
+	}
+	return y, true
+}
@@ -0,0 +1,52 @@
+package cornice
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestTinyTerminals(t *testing.T) {
+	tests := []struct {
+		height  int
+		compact bool
+		hint    bool
+	}{
+		{1, true, true},
+		{3, true, false},
+		{5, true, false},
+	}
+	for _, tt := range tests {
+		m := initialModel(WithColorMode(ColorNever))
+		m = drive(m, tea.WindowSizeMsg{Width: 80, Height: tt.height})
+		v := m.View()
+		if m.compact() != tt.compact {
+			t.Errorf("height %d: compact = %v, want %v", tt.height, m.compact(), tt.compact)
+		}
+		if strings.Contains(v, tooSmallHint) != tt.hint {
+			t.Errorf("height %d: view %q, want the too-small hint %v", tt.height, v, tt.hint)
+		}
+		if h := lipgloss.Height(v); h > tt.height {
+			t.Errorf("height %d: view is %d rows", tt.height, h)
+		}
+		// Clicks anywhere, including past the bottom, must not panic.
+		for y := -1; y <= tt.height+1; y++ {
+			m = drive(m, click(1, y), click(40, y))
+		}
+	}
+}
+
+func TestCompactViewMarksFocus(t *testing.T) {
+	m := drive(initialModel(WithColorMode(ColorNever)), tea.WindowSizeMsg{Width: 80, Height: 3})
+	lines := strings.Split(m.compactView(), "\n")
+	if len(lines) < 2 || !strings.HasPrefix(lines[0], "> ") || strings.HasPrefix(lines[1], "> ") {
+		t.Fatalf("the focused username isn't marked:\n%s", m.View())
+	}
+	ox, oy := m.formOrigin()
+	m = drive(m, click(ox+1, oy+1))
+	if m.focusedField() != fieldPassword {
+		t.Errorf("clicking the second row focused %v, want the password", m.focusedField())
+	}
+}
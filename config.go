@@ -0,0 +1,113 @@
+package cornice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Config holds defaults read from the config file, e.g.
+//
+//	{
+//	  "theme": "dark",
+//	  "colors": {"focused": "#00AFFF"},
+//	  "max_form_width": 60,
+//	  "flags": {"status-bar": "true"}
+//	}
+type Config struct {
+	// Theme names a built-in theme, as accepted by ParseTheme.
+	Theme string `json:"theme"`
+	// Colors override the theme's colors; empty ones are kept.
+	Colors ThemeColors `json:"colors"`
+	// MaxFormWidth and MaxFormHeight cap the form as WithMaxFormSize does.
+	MaxFormWidth  int `json:"max_form_width"`
+	MaxFormHeight int `json:"max_form_height"`
+	// Flags holds default values for the cornice command's flags, by
+	// name. Flags given on the command line take precedence.
+	Flags map[string]string `json:"flags"`
+}
+
+// ThemeColors are the colors of a theme that a config file may override.
+type ThemeColors struct {
+	Focused string `json:"focused"`
+	Blurred string `json:"blurred"`
+	Title   string `json:"title"`
+}
+
+// ConfigPath returns the location of the config file under the user's
+// config directory.
+func ConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cornice", "config.json"), nil
+}
+
+// LoadConfig reads the config file. A missing file yields the zero Config
+// and no error.
+func LoadConfig() (Config, error) {
+	var c Config
+	path, err := ConfigPath()
+	if err != nil {
+		return c, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// ApplyColors returns t with the configured colors in place of its own.
+func (c Config) ApplyColors(t Theme) Theme {
+	if t.light != nil {
+		light := c.ApplyColors(*t.light)
+		t.light = &light
+	}
+	if c.Colors.Focused != "" {
+		t.Focused = t.Focused.BorderForeground(lipgloss.Color(c.Colors.Focused))
+	}
+	if c.Colors.Blurred != "" {
+		t.Blurred = t.Blurred.BorderForeground(lipgloss.Color(c.Colors.Blurred))
+	}
+	if c.Colors.Title != "" {
+		t.Title = t.Title.Foreground(lipgloss.Color(c.Colors.Title))
+	}
+	return t
+}
+
+// WithConfig applies the config's theme, colors and size caps. Options
+// given after it override them. An unknown theme is logged and ignored.
+func WithConfig(c Config) Option {
+	return func(m *model) {
+		t := m.theme
+		if c.Theme != "" {
+			parsed, err := ParseTheme(c.Theme)
+			if err != nil {
+				log.Printf("config: %v", err)
+			} else {
+				t = parsed
+			}
+		}
+		WithTheme(c.ApplyColors(t))(m)
+		if c.MaxFormWidth > 0 {
+			m.maxFormWidth = c.MaxFormWidth
+		}
+		if c.MaxFormHeight > 0 {
+			m.maxFormHeight = c.MaxFormHeight
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package cornice
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const sampleConfig = `{
+  "theme": "dark",
+  "colors": {"focused": "#00AFFF", "title": "#FF8700"},
+  "max_form_width": 60,
+  "flags": {"status-bar": "true"}
+}`
+
+// writeConfig installs data as the config file of a temporary config
+// directory.
+func writeConfig(t *testing.T, data string) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path, err := ConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	writeConfig(t, sampleConfig)
+	got, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Config{
+		Theme:        "dark",
+		Colors:       ThemeColors{Focused: "#00AFFF", Title: "#FF8700"},
+		MaxFormWidth: 60,
+		Flags:        map[string]string{"status-bar": "true"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadConfig() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadConfigMissing(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	got, err := LoadConfig()
+	if err != nil || !reflect.DeepEqual(got, Config{}) {
+		t.Errorf("LoadConfig() = %+v, %v; want the zero Config", got, err)
+	}
+}
+
+func TestLoadConfigMalformed(t *testing.T) {
+	writeConfig(t, "{")
+	if _, err := LoadConfig(); err == nil {
+		t.Error("LoadConfig accepted a malformed file")
+	}
+}
+
+func TestWithConfigColorsWithoutTheme(t *testing.T) {
+	c := Config{Colors: ThemeColors{Focused: "#00AFFF"}}
+	m := initialModel(WithTheme(DefaultTheme()), WithConfig(c))
+	if got := m.theme.Focused.GetBorderTopForeground(); got != lipgloss.Color("#00AFFF") {
+		t.Errorf("focused border = %v, want the configured color", got)
+	}
+}
+
+func TestWithConfigPrecedence(t *testing.T) {
+	c := Config{Theme: "dark", MaxFormWidth: 60, MaxFormHeight: 20}
+	m := initialModel(WithConfig(c), WithMaxFormSize(40, 0))
+	if m.maxFormWidth != 40 || m.maxFormHeight != 0 {
+		t.Errorf("max form size = %dx%d, want the later option's 40x0", m.maxFormWidth, m.maxFormHeight)
+	}
+
+	m = initialModel(WithMaxFormSize(40, 0), WithConfig(c))
+	if m.maxFormWidth != 60 || m.maxFormHeight != 20 {
+		t.Errorf("max form size = %dx%d, want the config's 60x20", m.maxFormWidth, m.maxFormHeight)
+	}
+}
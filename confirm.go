@@ -0,0 +1,116 @@
+package cornice
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var confirmStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("#FFA500")).
+	Padding(0, 1)
+
+// confirmation is a yes/no question shown over the form. hint describes
+// the answers.
+type confirmation struct {
+	question string
+	hint     string
+	onYes    func(model) (tea.Model, tea.Cmd)
+}
+
+// ask shows question over the form, running onYes if the user answers y.
+func (m model) ask(question string, onYes func(model) (tea.Model, tea.Cmd)) model {
+	m.confirming = &confirmation{question: question, hint: "(y/n)", onYes: onYes}
+	return m
+}
+
+// updateConfirming handles keys while a confirmation is shown: y runs the
+// pending action, n or Esc dismisses it. Everything else is ignored.
+func (m model) updateConfirming(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "y", "Y":
+			c := m.confirming
+			m.confirming = nil
+			return c.onYes(m)
+		case "n", "N", "esc":
+			m.confirming = nil
+		case "ctrl+c":
+			return m, tea.Quit
+		}
+	case tea.WindowSizeMsg:
+		m = m.resize(msg)
+	}
+	return m, nil
+}
+
+// confirmView renders the pending confirmation centered on screen.
+func (m model) confirmView() string {
+	c := m.confirming
+	sep := " "
+	if strings.Contains(c.question, "\n") {
+		sep = "\n\n"
+	}
+	box := m.style(confirmStyle).Render(c.question + sep + c.hint)
+	return m.renderer.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// hasInput reports whether anything has been typed into the form.
+func (m model) hasInput() bool {
+	for _, f := range m.fields() {
+		if in := m.input(f); in != nil && in.Value() != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// interrupt handles Ctrl+C: it quits straight away on an empty form, and
+// otherwise asks first so typed credentials aren't lost by accident.
+func (m model) interrupt() (tea.Model, tea.Cmd) {
+	if !m.hasInput() {
+		return m, tea.Quit
+	}
+	return m.ask("Quit without submitting?", func(m model) (tea.Model, tea.Cmd) {
+		return m, tea.Quit
+	}), nil
+}
+
+// emptyExpectedFields lists the fields that are blank but usually filled.
+func (m model) emptyExpectedFields() []string {
+	var empty []string
+	if m.usernameInput.Value() == "" {
+		empty = append(empty, "username")
+	}
+	if m.passwordInput.Value() == "" {
+		empty = append(empty, "password")
+	}
+	return empty
+}
+
+// emptyFieldsQuestion phrases the partial-submit warning.
+func emptyFieldsQuestion(empty []string) string {
+	return "Submit without " + strings.Join(empty, " and ") + "?"
+}
+
+// accept moves on from a valid form: to the review summary when enabled,
+// otherwise straight to finishing.
+func (m model) accept() (tea.Model, tea.Cmd) {
+	if !m.review {
+		return m.finish()
+	}
+	m = m.ask(m.reviewSummary(), model.finish)
+	m.confirming.hint = "Confirm (y) / Edit (n)"
+	return m, nil
+}
+
+// reviewSummary lists the values about to be submitted, with the password
+// masked.
+func (m model) reviewSummary() string {
+	mask := strings.Repeat(string(m.passwordInput.EchoCharacter), utf8.RuneCountInString(m.passwordInput.Value()))
+	return m.fieldTitle(fieldUsername) + ": " + m.usernameValue() + "\nPassword: " + mask
+}
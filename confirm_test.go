@@ -0,0 +1,43 @@
+package cornice
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var ctrlC = tea.KeyMsg{Type: tea.KeyCtrlC}
+
+func TestInterruptConfirmAndQuit(t *testing.T) {
+	m := drive(initialModel(WithColorMode(ColorNever)), script([]tea.Msg{tea.WindowSizeMsg{Width: 80, Height: 24}}, keys("alice"))...)
+
+	next, cmd := m.Update(ctrlC)
+	m = next.(model)
+	if quits(cmd) {
+		t.Fatal("Ctrl+C quit with a username typed")
+	}
+	if !strings.Contains(m.View(), "Quit without submitting? (y/n)") {
+		t.Fatalf("the view lacks the quit prompt:\n%s", m.View())
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}); !quits(cmd) {
+		t.Error("y didn't quit")
+	}
+}
+
+func TestInterruptConfirmAndCancel(t *testing.T) {
+	m := drive(initialModel(), keys("alice")...)
+	m = drive(m, ctrlC, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if m.confirming != nil || m.done {
+		t.Fatalf("confirming = %v, done = %v after n; want back to editing", m.confirming, m.done)
+	}
+	if v := m.usernameInput.Value(); v != "alice" {
+		t.Errorf("username = %q after cancelling, want it kept", v)
+	}
+}
+
+func TestInterruptEmptyQuits(t *testing.T) {
+	if _, cmd := initialModel().Update(ctrlC); !quits(cmd) {
+		t.Error("Ctrl+C on an empty form didn't quit straight away")
+	}
+}
@@ -0,0 +1,80 @@
+package cornice
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"log"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// controlMsg is a command received on the control stream, e.g.
+// {"cmd":"set_username","value":"alice"}.
+type controlMsg struct {
+	Cmd   string `json:"cmd"`
+	Value string `json:"value"`
+}
+
+// readControl reads newline-delimited JSON commands from r and injects them
+// into the program. Malformed lines are logged and skipped.
+func readControl(r io.Reader, p *tea.Program) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var msg controlMsg
+		if err := json.Unmarshal(line, &msg); err != nil || msg.Cmd == "" {
+			log.Printf("control: ignoring malformed line %q", line)
+			continue
+		}
+		p.Send(msg)
+	}
+	if err := scanner.Err(); err != nil {
+		log.Printf("control: %v", err)
+	}
+}
+
+func (m model) handleControl(msg controlMsg) (tea.Model, tea.Cmd) {
+	switch msg.Cmd {
+	case "set_username":
+		m.usernameInput.SetValue(msg.Value)
+	case "set_password":
+		m.passwordInput.SetValue(msg.Value)
+	case "set_confirm":
+		m.confirmInput.SetValue(msg.Value)
+	case "set_totp":
+		m.totpInput.SetValue(msg.Value)
+	case "add_field":
+		m = m.addField(msg.Value, "")
+	case "focus":
+		i, ok := m.fieldIndex(msg.Value)
+		if !ok {
+			log.Printf("control: unknown field %q", msg.Value)
+			break
+		}
+		m = m.setFocus(i)
+	case "reset":
+		m = m.reset()
+	case "submit":
+		return m.submit()
+	case "quit":
+		return m, tea.Quit
+	default:
+		log.Printf("control: unknown command %q", msg.Cmd)
+	}
+	return m, nil
+}
+
+// fieldIndex maps a field name, as given by fieldName, to its position in
+// the form.
+func (m model) fieldIndex(name string) (int, bool) {
+	for i, f := range m.fields() {
+		if m.fieldName(f) == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,276 @@
+// Package cornice provides a terminal form for entering a username and
+// password.
+package cornice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ErrCancelled is returned by Run and Prompt when the user quits without
+// submitting.
+var ErrCancelled = errors.New("cornice: cancelled")
+
+// ErrTimeout is returned by Run and Prompt when the form closes itself
+// after WithIdleTimeout's period of inactivity.
+var ErrTimeout = errors.New("cornice: timed out")
+
+// Credentials are the values submitted through the form.
+type Credentials struct {
+	Username string
+	Password string
+	// TOTP is the one-time code, when WithTOTP asks for one.
+	TOTP string
+	// Fields holds the values of fields added with Form.AddField, by label.
+	Fields map[string]string
+	// Guest is set, with every value empty, when the user continued as
+	// guest through WithGuest.
+	Guest bool
+}
+
+// credentials extracts the submitted values from a finished model.
+func (m model) credentials() Credentials {
+	c := Credentials{
+		Username: m.typedUsername(),
+		Password: m.fieldValue(fieldPassword),
+		Guest:    m.guest,
+	}
+	if m.totp {
+		c.TOTP = m.fieldValue(fieldTOTP)
+	}
+	for _, x := range m.extras {
+		if c.Fields == nil {
+			c.Fields = make(map[string]string, len(m.extras))
+		}
+		c.Fields[x.label] = m.untab(x.input.Value())
+	}
+	return c
+}
+
+// resultMode selects how the submitted credentials are reported.
+type resultMode int
+
+const (
+	// resultText shows the result screen.
+	resultText resultMode = iota
+	// resultJSON prints the credentials as JSON once the program exits.
+	resultJSON
+	// resultLines writes the username and password, one per line, to
+	// runConfig.credentialsOut once the program exits.
+	resultLines
+)
+
+// printResult reports the credentials once the program has exited, as the
+// result mode selects; out receives JSON. The result screen has nothing
+// to print.
+func (m model) printResult(out io.Writer) error {
+	switch m.resultMode {
+	case resultJSON:
+		return printJSON(out, m.credentials())
+	case resultLines:
+		return printLines(m.run.credentialsOut, m.credentials())
+	}
+	return nil
+}
+
+// printJSON writes the credentials to w as a single JSON object.
+func printJSON(w io.Writer, c Credentials) error {
+	data, err := json.Marshal(struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		TOTP     string `json:"totp,omitempty"`
+		Guest    bool   `json:"guest,omitempty"`
+	}{c.Username, c.Password, c.TOTP, c.Guest})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// printLines writes the username and password to w on separate lines, as
+// credential helpers expect, followed by the one-time code if there is one.
+// A guest has no credentials, so nothing is written.
+func printLines(w io.Writer, c Credentials) error {
+	if c.Guest {
+		return nil
+	}
+	_, err := fmt.Fprintf(w, "%s\n%s\n", c.Username, c.Password)
+	if err == nil && c.TOTP != "" {
+		_, err = fmt.Fprintf(w, "%s\n", c.TOTP)
+	}
+	return err
+}
+
+// runConfig holds settings that affect how the program runs rather than
+// the form itself.
+type runConfig struct {
+	input   io.Reader
+	output  io.Writer
+	control bool
+	metrics bool
+	debug   bool
+
+	// nonInteractive reads the credentials as two lines of input instead
+	// of showing the form.
+	nonInteractive bool
+
+	// colorMode selects whether the form is drawn in color.
+	colorMode ColorMode
+
+	// credentialsOut receives the credentials in resultLines mode.
+	credentialsOut io.Writer
+
+	// eventLog, when set, receives a line for every key, mouse and resize
+	// message handled.
+	eventLog io.Writer
+
+	// altScreen runs the form on the alternate screen instead of clearing
+	// the terminal, so the previous content comes back on exit.
+	altScreen bool
+}
+
+// Prompt shows the form and returns the submitted username and password.
+// A guest gets empty values; use Run to tell guests apart.
+func Prompt(opts ...Option) (username, password string, err error) {
+	c, err := Run(opts...)
+	return c.Username, c.Password, err
+}
+
+// Run shows the form and returns the submitted credentials.
+func Run(opts ...Option) (Credentials, error) {
+	return RunContext(context.Background(), opts...)
+}
+
+// RunContext is like Run but gives up when ctx is done, e.g. when a
+// parent operation times out, restoring the terminal and returning
+// ctx.Err().
+func RunContext(ctx context.Context, opts ...Option) (Credentials, error) {
+	return NewForm(opts...).Run(ctx)
+}
+
+// runProgram shows the form until it is submitted, cancelled or ctx is done.
+// started is called once, with the program before it starts, or with nil
+// when the credentials are read without one.
+func (m model) runProgram(ctx context.Context, started func(*tea.Program)) (Credentials, error) {
+	var input io.Reader = os.Stdin
+	if m.run.input != nil {
+		input = m.run.input
+	}
+
+	if m.run.nonInteractive {
+		started(nil)
+		out := io.Writer(os.Stdout)
+		if m.run.output != nil {
+			out = m.run.output
+		}
+		fm, err := m.completeFromInput(input, out)
+		if err != nil {
+			return Credentials{}, err
+		}
+		return fm.credentials(), nil
+	}
+
+	if m.mouseEnabled && !supportsMouse() {
+		m.mouseEnabled = false
+		if m.run.debug {
+			log.Printf("mouse disabled: TERM=%q does not support mouse reporting", os.Getenv("TERM"))
+		}
+	}
+
+	var progOpts []tea.ProgramOption
+	if m.mouseEnabled {
+		progOpts = append(progOpts, tea.WithMouseAllMotion())
+	}
+	if m.maskOnTerminalBlur {
+		progOpts = append(progOpts, tea.WithReportFocus())
+	}
+	if m.run.control {
+		// The input carries the control stream, so the keyboard is not read.
+		progOpts = append(progOpts, tea.WithInput(nil))
+	} else if m.run.input != nil {
+		progOpts = append(progOpts, tea.WithInput(input))
+	}
+	if m.run.output != nil {
+		progOpts = append(progOpts, tea.WithOutput(m.run.output))
+	}
+	altScreen, clearScreen := m.screenMode()
+	if altScreen {
+		progOpts = append(progOpts, tea.WithAltScreen())
+	}
+	if clearScreen {
+		clearTerminal(os.Stdout)
+	}
+
+	p := tea.NewProgram(m, progOpts...)
+	started(p)
+	// Quit rather than hand ctx to the program: Bubble Tea v1.1 can hang
+	// when its context is cancelled while it runs a batch of commands.
+	stop := context.AfterFunc(ctx, p.Quit)
+	defer stop()
+	if m.run.control {
+		go readControl(input, p)
+	}
+
+	final, err := p.Run()
+	if clearScreen {
+		clearTerminal(os.Stdout)
+	}
+	if ctx.Err() != nil {
+		return Credentials{}, ctx.Err()
+	}
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	fm, ok := final.(model)
+	if ok && fm.timedOut {
+		return Credentials{}, ErrTimeout
+	}
+	if !ok || !fm.done {
+		return Credentials{}, ErrCancelled
+	}
+	if fm.offerRemember && !fm.guest {
+		fm.saveRemembered()
+	}
+	if fm.run.metrics {
+		log.Printf("time to login: %s", fm.timeToLogin())
+	}
+	// The program has exited and restored the terminal, so the result
+	// isn't mixed with escape codes.
+	out := io.Writer(os.Stdout)
+	if fm.run.output != nil {
+		out = fm.run.output
+	}
+	if err := fm.printResult(out); err != nil {
+		return Credentials{}, err
+	}
+	if altScreen && fm.resultMode == resultText && !fm.quitOnSubmit {
+		// Leaving the alternate screen took the result screen with it.
+		if _, err := io.WriteString(out, fm.resultView()); err != nil {
+			return Credentials{}, err
+		}
+	}
+	return fm.credentials(), nil
+}
+
+// screenMode reports whether the form runs on the alternate screen and
+// whether the terminal is cleared around it. Only stdout is cleared;
+// embedders rendering elsewhere, inline mode and the alternate screen keep
+// their screen. Inline mode takes precedence over the alternate screen.
+func (m model) screenMode() (altScreen, clearScreen bool) {
+	if m.inline {
+		return false, false
+	}
+	if m.run.altScreen {
+		return true, false
+	}
+	return false, m.run.output == nil || m.run.output == io.Writer(os.Stdout)
+}
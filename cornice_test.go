@@ -0,0 +1,142 @@
+package cornice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runWithKeys runs the form reading typed as its keyboard. A form still
+// open after five seconds gives up with context.DeadlineExceeded.
+func runWithKeys(typed string, opts ...Option) (Credentials, error) {
+	opts = append([]Option{WithInput(strings.NewReader(typed)), WithOutput(io.Discard), WithColorMode(ColorNever)}, opts...)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return RunContext(ctx, opts...)
+}
+
+func TestCredentialsFromFinishedModel(t *testing.T) {
+	m := initialModel(WithTOTP())
+	m = m.addField("PIN", "")
+	m = drive(m, script(
+		keys("alice"), []tea.Msg{tea.KeyMsg{Type: tea.KeyTab}},
+		keys("secret"), []tea.Msg{tea.KeyMsg{Type: tea.KeyTab}},
+		keys("123456"), []tea.Msg{tea.KeyMsg{Type: tea.KeyTab}},
+		keys("0000"), []tea.Msg{tea.KeyMsg{Type: tea.KeyEnter}},
+	)...)
+	if !m.done {
+		t.Fatal("the form didn't complete")
+	}
+	got := m.credentials()
+	want := Credentials{Username: "alice", Password: "secret", TOTP: "123456", Fields: map[string]string{"PIN": "0000"}}
+	if got.Username != want.Username || got.Password != want.Password || got.TOTP != want.TOTP || got.Fields["PIN"] != "0000" || got.Guest {
+		t.Errorf("credentials = %+v, want %+v", got, want)
+	}
+}
+
+func TestRunReturnsCredentials(t *testing.T) {
+	c, err := runWithKeys("alice\tsecret\r", WithQuitOnSubmit())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Username != "alice" || c.Password != "secret" {
+		t.Errorf("Run() = %q/%q, want alice/secret", c.Username, c.Password)
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	for name, typed := range map[string]string{"esc": "\x1b", "ctrl+c": "\x03"} {
+		if _, err := runWithKeys(typed); !errors.Is(err, ErrCancelled) {
+			t.Errorf("%s: Run() error = %v, want ErrCancelled", name, err)
+		}
+	}
+}
+
+func TestPrintResult(t *testing.T) {
+	c := Credentials{Username: "alice", Password: "secret", TOTP: "123456"}
+	var b bytes.Buffer
+	if err := printJSON(&b, c); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"username":"alice","password":"secret","totp":"123456"}` + "\n"; b.String() != want {
+		t.Errorf("JSON = %q, want %q", b.String(), want)
+	}
+	b.Reset()
+	if err := printLines(&b, c); err != nil {
+		t.Fatal(err)
+	}
+	if want := "alice\nsecret\n123456\n"; b.String() != want {
+		t.Errorf("lines = %q, want %q", b.String(), want)
+	}
+}
+
+func TestCredentialsWrittenToFD(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	var screen bytes.Buffer
+	_, err = RunContext(context.Background(),
+		WithInput(strings.NewReader("alice\tpa55word\r")),
+		WithOutput(&screen),
+		WithColorMode(ColorNever),
+		WithCredentialsOutput(w),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "alice\npa55word\n" {
+		t.Errorf("fd received %q, want the username and password on separate lines", got)
+	}
+	if strings.Contains(screen.String(), "pa55word") {
+		t.Error("the password was echoed to the terminal")
+	}
+}
+
+func TestRunContextCancelReturnsPromptly(t *testing.T) {
+	in, keyboard := io.Pipe()
+	defer keyboard.Close()
+	var out bytes.Buffer
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := RunContext(ctx, WithInput(in), WithOutput(&out))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RunContext error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("RunContext took %v to return after a 100ms timeout", elapsed)
+	}
+}
+
+func TestRunContextCancel(t *testing.T) {
+	in, keyboard := io.Pipe()
+	defer keyboard.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		io.WriteString(keyboard, "alice")
+		cancel()
+	}()
+	c, err := RunContext(ctx, WithInput(in), WithOutput(io.Discard))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("RunContext error = %v, want context.Canceled", err)
+	}
+	if c.Username != "" {
+		t.Errorf("username = %q from a cancelled form, want none", c.Username)
+	}
+}
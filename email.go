@@ -0,0 +1,18 @@
+package cornice
+
+import "regexp"
+
+// emailPattern accepts addresses of the form local@domain.tld. It is
+// deliberately loose: the backend has the final word.
+var emailPattern = regexp.MustCompile(`^[^\s@]+@[^\s@]+\.[^\s@.]+$`)
+
+// emailError validates the username field as an email address.
+func (m model) emailError() string {
+	switch v := m.usernameValue(); {
+	case v == "":
+		return "Email is required"
+	case !emailPattern.MatchString(v):
+		return "Invalid email address"
+	}
+	return ""
+}
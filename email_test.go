@@ -0,0 +1,63 @@
+package cornice
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestEmailValidation(t *testing.T) {
+	tests := []struct {
+		email, want string
+	}{
+		{"alice@example.com", ""},
+		{"a.b+c@mail.example.org", ""},
+		{"", "Email is required"},
+		{"alice", "Invalid email address"},
+		{"alice@example", "Invalid email address"},
+		{"al ice@example.com", "Invalid email address"},
+		{"alice@@example.com", "Invalid email address"},
+		{"alice@example.", "Invalid email address"},
+	}
+	for _, tt := range tests {
+		m := initialModel(WithEmail())
+		m.usernameInput.SetValue(tt.email)
+		if got := m.fieldError(fieldUsername); got != tt.want {
+			t.Errorf("email %q: error %q, want %q", tt.email, got, tt.want)
+		}
+	}
+}
+
+func TestEmailBlocksSubmit(t *testing.T) {
+	m := initialModel(WithEmail())
+	m = drive(m, script(keys("alice"), []tea.Msg{tea.KeyMsg{Type: tea.KeyTab}}, keys("pw"), []tea.Msg{tea.KeyMsg{Type: tea.KeyEnter}})...)
+	if m.done || m.err(fieldUsername) != "Invalid email address" {
+		t.Fatalf("done = %v, error %q; want the malformed address rejected", m.done, m.err(fieldUsername))
+	}
+	m = drive(m, script(keys("@example.com"), []tea.Msg{tea.KeyMsg{Type: tea.KeyEnter}})...)
+	if !m.done {
+		t.Errorf("a valid address was rejected: %q", m.err(fieldUsername))
+	}
+}
+
+func TestEmailFocusOrder(t *testing.T) {
+	m := drive(initialModel(WithEmail(), WithColorMode(ColorNever)), tea.WindowSizeMsg{Width: 80, Height: 24})
+	if fs := m.fields(); fs[0] != fieldUsername || fs[1] != fieldPassword {
+		t.Errorf("fields = %v, want the email first, then the password", fs)
+	}
+	if m.focusedField() != fieldUsername || m.fieldName(fieldUsername) != "username" {
+		t.Errorf("starts on %v named %q, want the email field keeping the username's name", m.focusedField(), m.fieldName(fieldUsername))
+	}
+	view := m.View()
+	if !strings.Contains(view, "Email") || strings.Contains(view, "Username") {
+		t.Errorf("the first box isn't titled Email:\n%s", view)
+	}
+	if strings.Index(view, "Email") > strings.Index(view, "Password") {
+		t.Error("the email box isn't above the password")
+	}
+	ox, _ := m.formOrigin()
+	if got := drive(m, tea.KeyMsg{Type: tea.KeyTab}, click(ox+2, fieldRow(m, 0))); got.focusedField() != fieldUsername {
+		t.Errorf("click on the email box focused %v", got.focusedField())
+	}
+}
@@ -0,0 +1,28 @@
+package cornice
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// escapeWindow is how soon a second Esc must follow the one that cleared a
+// field to quit regardless.
+const escapeWindow = time.Second
+
+// escape handles Esc: on a field with text it clears the field, so typing
+// isn't lost to a stray key, while on an empty field, or straight after
+// clearing, it quits like Ctrl+C, asking first if other fields are filled.
+func (m model) escape() (tea.Model, tea.Cmd) {
+	now := m.now()
+	in := m.input(m.focusedField())
+	if in == nil || in.Value() == "" || (!m.escapedAt.IsZero() && now.Sub(m.escapedAt) < escapeWindow) {
+		return m.interrupt()
+	}
+	in.SetValue("")
+	m.escapedAt = now
+	if m.focusedField() == fieldUsername {
+		return m.usernameChanged()
+	}
+	return m, nil
+}
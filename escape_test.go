@@ -0,0 +1,82 @@
+package cornice
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var esc = tea.KeyMsg{Type: tea.KeyEsc}
+
+// quits reports whether cmd quits the program.
+func quits(cmd tea.Cmd) bool {
+	if cmd == nil {
+		return false
+	}
+	_, ok := cmd().(tea.QuitMsg)
+	return ok
+}
+
+// pressEsc sends Esc to m and returns the result.
+func pressEsc(m model) (model, tea.Cmd) {
+	next, cmd := m.Update(esc)
+	return next.(model), cmd
+}
+
+func TestEscClearsBeforeQuitting(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := typeInto(initialModel(), fieldUsername, "alice", 0, clock)
+
+	m, cmd := pressEsc(m)
+	if quits(cmd) {
+		t.Fatal("Esc on a filled field quit")
+	}
+	if v := m.usernameInput.Value(); v != "" {
+		t.Errorf("username = %q after Esc, want it cleared", v)
+	}
+
+	clock.Advance(escapeWindow / 2)
+	if _, cmd := pressEsc(m); !quits(cmd) {
+		t.Error("a second Esc within the window didn't quit")
+	}
+}
+
+func TestEscOnEmptyFieldQuits(t *testing.T) {
+	if _, cmd := pressEsc(initialModel()); !quits(cmd) {
+		t.Error("Esc on an empty field didn't quit")
+	}
+}
+
+func TestEscAfterWindowClearsAgain(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := typeInto(initialModel(), fieldUsername, "alice", 0, clock)
+	m, _ = pressEsc(m)
+
+	clock.Advance(escapeWindow)
+	m = typeInto(m, fieldUsername, "bob", 0, clock)
+	m, cmd := pressEsc(m)
+	if quits(cmd) {
+		t.Error("Esc after the window quit instead of clearing")
+	}
+	if v := m.usernameInput.Value(); v != "" {
+		t.Errorf("username = %q, want it cleared", v)
+	}
+}
+
+func TestEscOnEmptyFieldAsksWhenOthersFilled(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := typeInto(initialModel(), fieldUsername, "alice", 0, clock)
+	m = m.setFocus(1)
+
+	m, cmd := pressEsc(m)
+	if quits(cmd) {
+		t.Fatal("Esc on the empty password quit with a username typed")
+	}
+	if m.confirming == nil {
+		t.Fatal("Esc on the empty password didn't ask to quit")
+	}
+	if v := m.usernameInput.Value(); v != "alice" {
+		t.Errorf("username = %q, want it kept", v)
+	}
+}
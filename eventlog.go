@@ -0,0 +1,40 @@
+package cornice
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// logEvent writes a line describing msg, handled by before, and the state
+// it left the form in, for the input messages worth tracing. Other
+// messages are skipped. Text typed into secret fields is not logged.
+func logEvent(w io.Writer, msg tea.Msg, before, m model) {
+	var event string
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		key := msg.String()
+		if msg.Type == tea.KeyRunes && before.fieldSecret(before.focusedField()) {
+			key = "<secret>"
+		}
+		event = fmt.Sprintf("event=key key=%q", key)
+	case tea.MouseMsg:
+		event = fmt.Sprintf("event=mouse mouse=%q x=%d y=%d", msg.String(), msg.X, msg.Y)
+	case tea.WindowSizeMsg:
+		event = fmt.Sprintf("event=resize width=%d height=%d", msg.Width, msg.Height)
+	default:
+		return
+	}
+	fmt.Fprintf(w, "%s focus=%s done=%t\n", event, m.fieldName(m.focusedField()), m.done)
+}
+
+// eventLogWriter keeps the event log off stdout, where it would corrupt
+// the form.
+func eventLogWriter(w io.Writer) io.Writer {
+	if w == io.Writer(os.Stdout) {
+		return os.Stderr
+	}
+	return w
+}
@@ -0,0 +1,41 @@
+package cornice
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestEventLog(t *testing.T) {
+	var log bytes.Buffer
+	m := initialModel(WithLogger(&log))
+	m = drive(m, script(
+		[]tea.Msg{tea.WindowSizeMsg{Width: 80, Height: 24}},
+		keys("a"),
+		[]tea.Msg{tea.KeyMsg{Type: tea.KeyTab}},
+		keys("s"),
+		[]tea.Msg{tea.KeyMsg{Type: tea.KeyEnter}},
+	)...)
+
+	want := `event=resize width=80 height=24 focus=username done=false
+event=key key="a" focus=username done=false
+event=key key="tab" focus=password done=false
+event=key key="<secret>" focus=password done=false
+event=key key="enter" focus=password done=true
+`
+	if got := log.String(); got != want {
+		t.Errorf("log =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestEventLogNeverStdout(t *testing.T) {
+	if w := eventLogWriter(os.Stdout); w != os.Stderr {
+		t.Errorf("eventLogWriter(os.Stdout) = %v, want os.Stderr", w)
+	}
+	var buf bytes.Buffer
+	if w := eventLogWriter(&buf); w != &buf {
+		t.Error("eventLogWriter replaced a writer other than stdout")
+	}
+}
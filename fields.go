@@ -0,0 +1,271 @@
+package cornice
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+// field identifies one of the form's inputs. Fields added at runtime with
+// AddField follow the built-in ones, numbered from numFields.
+type field int
+
+const (
+	fieldUsername field = iota
+	fieldPassword
+	fieldConfirm
+	fieldRemember
+	fieldTOTP
+	fieldForgot
+	numFields
+)
+
+// extraField is a field added at runtime with AddField.
+type extraField struct {
+	label    string
+	input    textinput.Model
+	secret   bool
+	validate func(string) error
+	err      string
+}
+
+// FieldOption configures a field added with Form.AddField.
+type FieldOption func(*extraField)
+
+// WithFieldSecret masks the field like the password.
+func WithFieldSecret() FieldOption {
+	return func(e *extraField) {
+		e.secret = true
+		e.input.EchoMode = textinput.EchoPassword
+	}
+}
+
+// WithFieldValidator checks the field's value with validate on submit.
+func WithFieldValidator(validate func(string) error) FieldOption {
+	return func(e *extraField) {
+		e.validate = validate
+	}
+}
+
+// addField appends a field labelled label to the form; see Form.AddField.
+func (m model) addField(label, placeholder string, opts ...FieldOption) model {
+	e := extraField{label: label, input: textinput.New()}
+	e.input.Placeholder = placeholder
+	for _, opt := range opts {
+		opt(&e)
+	}
+	e.input.EchoCharacter = m.echoCharacter
+	m.extras = append(m.extras[:len(m.extras):len(m.extras)], e)
+	m = m.applyEchoMode()
+	return m.scrollToFocus()
+}
+
+// extra returns the runtime field f, or nil for a built-in one.
+func (m *model) extra(f field) *extraField {
+	if f < numFields {
+		return nil
+	}
+	return &m.extras[f-numFields]
+}
+
+// fieldTitle is the heading shown in f's box.
+func (m model) fieldTitle(f field) string {
+	switch f {
+	case fieldUsername:
+		if m.email {
+			return "Email"
+		}
+		return "Username"
+	case fieldPassword:
+		return "Password"
+	case fieldConfirm:
+		return "Confirm Password"
+	case fieldRemember:
+		return "Remember me"
+	case fieldTOTP:
+		return "One-time code"
+	case fieldForgot:
+		return "Forgot password?"
+	}
+	return m.extra(f).label
+}
+
+// fieldName identifies f to embedders and on the control stream:
+// "username", "password", "confirm", "remember", "totp", "forgot", or the
+// label of a runtime field. The username keeps its name when it asks for
+// an email address.
+func (m model) fieldName(f field) string {
+	switch f {
+	case fieldUsername:
+		return "username"
+	case fieldPassword:
+		return "password"
+	case fieldConfirm:
+		return "confirm"
+	case fieldRemember:
+		return "remember"
+	case fieldTOTP:
+		return "totp"
+	case fieldForgot:
+		return "forgot"
+	}
+	return m.extra(f).label
+}
+
+// fieldSecret reports whether f is masked like the password.
+func (m model) fieldSecret(f field) bool {
+	if e := m.extra(f); e != nil {
+		return e.secret
+	}
+	return f == fieldPassword || f == fieldConfirm
+}
+
+// fields returns the active fields in focus order. The confirm field is
+// only shown in register mode, the one-time code when asked for and the
+// remember checkbox when offered; runtime fields follow, and the forgot
+// password action comes last, below every input.
+func (m model) fields() []field {
+	fs := []field{fieldUsername, fieldPassword}
+	if m.register {
+		fs = append(fs, fieldConfirm)
+	}
+	if m.totp {
+		fs = append(fs, fieldTOTP)
+	}
+	if m.offerRemember {
+		fs = append(fs, fieldRemember)
+	}
+	for i := range m.extras {
+		fs = append(fs, numFields+field(i))
+	}
+	if m.forgotPassword != nil {
+		fs = append(fs, fieldForgot)
+	}
+	return fs
+}
+
+// fieldCount is the number of focusable fields in the form.
+func (m model) fieldCount() int {
+	return len(m.fields())
+}
+
+// fieldIndexOf returns the position of f in the form, if it is shown.
+func (m model) fieldIndexOf(f field) (int, bool) {
+	for i, g := range m.fields() {
+		if g == f {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// focusedField is the field that currently has focus.
+func (m model) focusedField() field {
+	return m.fields()[m.focused]
+}
+
+// input returns the text input backing f, or nil for the remember
+// checkbox and the forgot password action.
+func (m *model) input(f field) *textinput.Model {
+	if e := m.extra(f); e != nil {
+		return &e.input
+	}
+	switch f {
+	case fieldPassword:
+		return &m.passwordInput
+	case fieldConfirm:
+		return &m.confirmInput
+	case fieldTOTP:
+		return &m.totpInput
+	case fieldRemember, fieldForgot:
+		return nil
+	}
+	return &m.usernameInput
+}
+
+// fieldValue is the value f would be submitted with. The remember
+// checkbox reads as "x" when ticked.
+func (m model) fieldValue(f field) string {
+	switch f {
+	case fieldUsername:
+		return m.usernameValue()
+	case fieldRemember:
+		if m.remember {
+			return "x"
+		}
+		return ""
+	case fieldForgot:
+		return ""
+	}
+	return m.untab(m.input(f).Value())
+}
+
+// err is the error currently shown for f.
+func (m model) err(f field) string {
+	if e := m.extra(f); e != nil {
+		return e.err
+	}
+	return m.errs[f]
+}
+
+// setErr sets the error shown for f.
+func (m *model) setErr(f field, err string) {
+	if e := m.extra(f); e != nil {
+		e.err = err
+		return
+	}
+	m.errs[f] = err
+}
+
+// fieldError returns the validation error for f, if any.
+func (m model) fieldError(f field) string {
+	if e := m.extra(f); e != nil {
+		if e.validate == nil {
+			return ""
+		}
+		if err := e.validate(m.fieldValue(f)); err != nil {
+			return err.Error()
+		}
+		return ""
+	}
+	switch f {
+	case fieldPassword:
+		return m.passwordLength.check("Password", m.fieldValue(fieldPassword))
+	case fieldConfirm:
+		return m.confirmError()
+	case fieldTOTP:
+		return m.totpError()
+	case fieldRemember, fieldForgot:
+		return ""
+	}
+	return m.usernameError()
+}
+
+// confirmError checks the confirm field: it must match the password and
+// pass its own validator, if one is set.
+func (m model) confirmError() string {
+	v := m.fieldValue(fieldConfirm)
+	if v != m.fieldValue(fieldPassword) {
+		return "Passwords do not match"
+	}
+	if m.confirmValidate != nil {
+		if err := m.confirmValidate(v); err != nil {
+			return err.Error()
+		}
+	}
+	return ""
+}
+
+// liveConfirmError is the confirm error shown while a secret field is
+// edited. An empty confirmation isn't flagged, nor is one still being typed
+// that is so far a prefix of the password.
+func (m model) liveConfirmError(editing field) string {
+	v, pw := m.fieldValue(fieldConfirm), m.fieldValue(fieldPassword)
+	if v == "" {
+		return ""
+	}
+	if editing == fieldConfirm && v != pw && strings.HasPrefix(pw, v) {
+		return ""
+	}
+	return m.confirmError()
+}
@@ -0,0 +1,93 @@
+package cornice
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const mismatch = "Passwords do not match"
+
+func TestConfirmTracksPasswordEdits(t *testing.T) {
+	tab := []tea.Msg{tea.KeyMsg{Type: tea.KeyTab}}
+	m := drive(initialModel(WithRegister()), script(tab, keys("secret"), tab, keys("secret"))...)
+	if err := m.err(fieldConfirm); err != "" {
+		t.Fatalf("matching passwords show %q", err)
+	}
+
+	m = drive(m, script([]tea.Msg{tea.KeyMsg{Type: tea.KeyShiftTab}}, keys("x"))...)
+	if err := m.err(fieldConfirm); err != mismatch {
+		t.Errorf("after editing the password, confirm error = %q, want %q", err, mismatch)
+	}
+	m = drive(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	if err := m.err(fieldConfirm); err != "" {
+		t.Errorf("after restoring the password, confirm error = %q, want none", err)
+	}
+}
+
+func TestConfirmPrefixNotFlaggedWhileTyping(t *testing.T) {
+	m := initialModel(WithRegister())
+	m.passwordInput.SetValue("secret")
+	m = drive(m, script(
+		[]tea.Msg{tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyTab}},
+		keys("sec"),
+	)...)
+	if err := m.err(fieldConfirm); err != "" {
+		t.Errorf("a confirmation still being typed shows %q", err)
+	}
+	m = drive(m, keys("x")...)
+	if err := m.err(fieldConfirm); err != mismatch {
+		t.Errorf("confirm error = %q once it diverges, want %q", err, mismatch)
+	}
+}
+
+func TestConfirmValidator(t *testing.T) {
+	m := initialModel(WithRegister(), WithConfirmValidator(func(v string) error {
+		if v == "password" {
+			return errors.New("Too common")
+		}
+		return nil
+	}))
+	m.passwordInput.SetValue("password")
+	m.confirmInput.SetValue("password")
+	if err := m.fieldError(fieldConfirm); err != "Too common" {
+		t.Errorf("confirm error = %q, want the validator's", err)
+	}
+	m.confirmInput.SetValue("other")
+	if err := m.fieldError(fieldConfirm); err != mismatch {
+		t.Errorf("confirm error = %q, want the match check first", err)
+	}
+}
+
+func TestInitialFocus(t *testing.T) {
+	tests := []struct {
+		index int
+		want  field
+	}{
+		{0, fieldUsername},
+		{1, fieldPassword},
+		{2, fieldConfirm},
+		{3, fieldTOTP},
+		{-5, fieldUsername},
+		{99, fieldTOTP},
+	}
+	for _, tt := range tests {
+		m := initialModel(WithRegister(), WithTOTP(), WithInitialFocus(tt.index))
+		if got := m.focusedField(); got != tt.want {
+			t.Errorf("WithInitialFocus(%d) focused %v, want %v", tt.index, got, tt.want)
+		}
+		for i, f := range m.fields() {
+			if got := m.input(f).Focused(); got != (i == m.focused) {
+				t.Errorf("WithInitialFocus(%d): %v focused = %v", tt.index, f, got)
+			}
+		}
+	}
+}
+
+func TestInitialFocusOverridesPrefill(t *testing.T) {
+	m := initialModel(WithUsername("alice"), WithInitialFocus(0))
+	if m.focusedField() != fieldUsername {
+		t.Errorf("focused %v, want the username despite the prefill", m.focusedField())
+	}
+}
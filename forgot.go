@@ -0,0 +1,64 @@
+package cornice
+
+import (
+	"log"
+	"os/exec"
+	"runtime"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// forgotLinkStyle underlines the forgot password action like a link.
+var forgotLinkStyle = previewStyle.Underline(true)
+
+// forgotView renders the forgot password action.
+func (m model) forgotView(focused bool) string {
+	style := m.style(forgotLinkStyle)
+	if focused {
+		style = m.theme.Title.Underline(true)
+	}
+	return style.Render(m.fieldTitle(fieldForgot))
+}
+
+// updateForgot handles keys while the forgot password action is focused:
+// Space triggers it like Enter and other input is ignored.
+func (m model) updateForgot(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.Type == tea.KeySpace {
+		return m, m.forgot()
+	}
+	return m, nil
+}
+
+// forgot runs the forgot password callback in the background, leaving
+// the form as it is.
+func (m model) forgot() tea.Cmd {
+	fn := m.forgotPassword
+	return func() tea.Msg {
+		fn()
+		return nil
+	}
+}
+
+// openURL opens url in the user's browser without waiting for it.
+func openURL(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
+
+// OpenURL returns a forgot password callback, for WithForgotPassword,
+// that opens url in the user's browser.
+func OpenURL(url string) func() {
+	return func() {
+		if err := openURL(url); err != nil {
+			log.Printf("forgot password: %v", err)
+		}
+	}
+}
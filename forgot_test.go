@@ -0,0 +1,35 @@
+package cornice
+
+import (
+	"sync/atomic"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestForgotPassword(t *testing.T) {
+	var calls atomic.Int32
+	m := initialModel(WithForgotPassword(func() { calls.Add(1) }))
+	m = drive(m, tea.WindowSizeMsg{Width: 80, Height: 24})
+	if got := m.fields()[len(m.fields())-1]; got != fieldForgot {
+		t.Fatalf("last field = %v, want the forgot password action", got)
+	}
+
+	m = drive(m, script(keys("alice"), []tea.Msg{tea.KeyMsg{Type: tea.KeyShiftTab}, tea.KeyMsg{Type: tea.KeyEnter}})...)
+	if m.focusedField() != fieldForgot || m.done {
+		t.Fatalf("focused %v, done = %v; want the action triggered without submitting", m.focusedField(), m.done)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("Enter called the callback %d times, want once", n)
+	}
+
+	m = drive(m, tea.KeyMsg{Type: tea.KeySpace})
+	if n := calls.Load(); n != 2 || m.done {
+		t.Errorf("Space: calls = %d, done = %v; want the callback again", n, m.done)
+	}
+
+	m = drive(m.setFocus(0), click(40, fieldRow(m, len(m.fields())-1)))
+	if n := calls.Load(); n != 3 || m.done {
+		t.Errorf("click: calls = %d, done = %v; want the callback again", n, m.done)
+	}
+}
@@ -0,0 +1,79 @@
+package cornice
+
+import (
+	"context"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Form is a form that can be changed while it is shown, e.g. to ask for a
+// one-time code once the server requires it. Its methods are safe to call
+// from any goroutine.
+type Form struct {
+	mu      sync.Mutex
+	m       model
+	program *tea.Program
+}
+
+// NewForm returns a form configured with opts, ready to Run.
+func NewForm(opts ...Option) *Form {
+	return &Form{m: initialModel(opts...)}
+}
+
+// Run shows the form and returns the submitted credentials, like
+// RunContext.
+func (f *Form) Run(ctx context.Context) (Credentials, error) {
+	// The lock is held until the program can take messages, so a change
+	// made meanwhile isn't applied to a model that has already been copied.
+	f.mu.Lock()
+	c, err := f.m.runProgram(ctx, func(p *tea.Program) {
+		f.program = p
+		f.mu.Unlock()
+	})
+	f.mu.Lock()
+	f.program = nil
+	f.mu.Unlock()
+	return c, err
+}
+
+// addFieldMsg asks a running form to add a field; see Form.AddField.
+type addFieldMsg struct {
+	label       string
+	placeholder string
+	opts        []FieldOption
+}
+
+// AddField appends a field labelled label to the form, also while it is
+// shown, e.g. to ask for a one-time code once the server requires it. It
+// takes part in focus cycling, layout, validation and the
+// result like the built-in fields.
+func (f *Form) AddField(label, placeholder string, opts ...FieldOption) {
+	f.apply(addFieldMsg{label, placeholder, opts})
+}
+
+// apply hands msg to the running program, or applies it to the form that
+// the next Run shows.
+func (f *Form) apply(msg tea.Msg) {
+	f.mu.Lock()
+	p := f.program
+	if p == nil {
+		next, _ := f.m.update(msg)
+		f.m = next.(model)
+	}
+	f.mu.Unlock()
+	if p != nil {
+		p.Send(msg)
+	}
+}
+
+// resetMsg asks a running form to clear itself; see Form.Reset.
+type resetMsg struct{}
+
+// Reset clears the form for another attempt, e.g. after the credentials
+// were rejected: every value, error and the result are cleared and the
+// username is focused again. The theme and all options are kept, as are
+// the failed attempts counting towards a lockout.
+func (f *Form) Reset() {
+	f.apply(resetMsg{})
+}
@@ -0,0 +1,56 @@
+package cornice
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+// runForm runs f on a pipe and returns the writer standing in for the
+// keyboard, and a channel receiving Run's result.
+func runForm(t *testing.T, opts ...Option) (*Form, io.WriteCloser, <-chan Credentials) {
+	t.Helper()
+	in, keyboard := io.Pipe()
+	t.Cleanup(func() { keyboard.Close() })
+	opts = append([]Option{WithInput(in), WithOutput(&bytes.Buffer{}), WithQuitOnSubmit(), WithColorMode(ColorNever)}, opts...)
+	f := NewForm(opts...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	result := make(chan Credentials, 1)
+	go func() {
+		c, err := f.Run(ctx)
+		if err != nil {
+			t.Errorf("Run: %v", err)
+		}
+		result <- c
+	}()
+	return f, keyboard, result
+}
+
+func TestFormAddFieldWhileRunning(t *testing.T) {
+	f, keyboard, result := runForm(t)
+
+	io.WriteString(keyboard, "alice\tsecret")
+	f.AddField("Code", "123456")
+	// The new field follows the password, so Tab reaches it.
+	io.WriteString(keyboard, "\t424242\r")
+
+	c := <-result
+	if c.Username != "alice" || c.Password != "secret" {
+		t.Errorf("got %q/%q, want alice/secret", c.Username, c.Password)
+	}
+	if got := c.Fields["Code"]; got != "424242" {
+		t.Errorf(`Fields["Code"] = %q, want "424242"`, got)
+	}
+}
+
+func TestFormAddFieldBeforeRun(t *testing.T) {
+	f := NewForm()
+	f.AddField("Code", "")
+	if got := f.m.fieldTitle(f.m.fields()[2]); got != "Code" {
+		t.Errorf("third field = %q, want the added Code", got)
+	}
+}
@@ -0,0 +1,131 @@
+package cornice
+
+import (
+	"crypto/rand"
+	"io"
+	"log"
+	"math/big"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// generateKey fills the password with a generated one while a secret
+// field has focus.
+const generateKey = "ctrl+g"
+
+// generatedRevealTime is how long a generated password stays visible.
+const generatedRevealTime = 5 * time.Second
+
+// Character sets for generated passwords.
+const (
+	CharsLower   = "abcdefghijklmnopqrstuvwxyz"
+	CharsUpper   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	CharsDigits  = "0123456789"
+	CharsSymbols = "!#$%&*+-=?@^_~"
+)
+
+// passwordGenerator builds random passwords of a given length, with at
+// least one character from each set.
+type passwordGenerator struct {
+	length   int
+	charsets []string
+	// limit, when positive, caps the length; it wins over covering every
+	// set, so the password always fits the field.
+	limit int
+	// rand is the source of randomness; tests may replace it.
+	rand io.Reader
+}
+
+// generate returns a new password. The length is raised to the number of
+// sets if it is shorter, so that each can be represented, and then capped
+// at limit.
+func (g passwordGenerator) generate() (string, error) {
+	n := max(g.length, len(g.charsets))
+	if g.limit > 0 {
+		n = min(n, g.limit)
+	}
+	var all string
+	pw := make([]byte, 0, n)
+	for _, set := range g.charsets {
+		all += set
+		if len(pw) == n {
+			continue
+		}
+		c, err := g.pick(set)
+		if err != nil {
+			return "", err
+		}
+		pw = append(pw, c)
+	}
+	for len(pw) < n {
+		c, err := g.pick(all)
+		if err != nil {
+			return "", err
+		}
+		pw = append(pw, c)
+	}
+	// Shuffle so the guaranteed characters aren't always in front.
+	for i := len(pw) - 1; i > 0; i-- {
+		j, err := g.intn(i + 1)
+		if err != nil {
+			return "", err
+		}
+		pw[i], pw[j] = pw[j], pw[i]
+	}
+	return string(pw), nil
+}
+
+func (g passwordGenerator) pick(set string) (byte, error) {
+	i, err := g.intn(len(set))
+	if err != nil {
+		return 0, err
+	}
+	return set[i], nil
+}
+
+func (g passwordGenerator) intn(n int) (int, error) {
+	i, err := rand.Int(g.rand, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(i.Int64()), nil
+}
+
+// generatedHideMsg masks a generated password again once it has been
+// shown for generatedRevealTime.
+type generatedHideMsg struct{}
+
+// generatePassword fills the password, and its confirmation in register
+// mode, with a generated one and reveals it briefly so it can be noted.
+// The password is kept within the field's character limit.
+func (m model) generatePassword() (tea.Model, tea.Cmd) {
+	g := *m.generator
+	g.limit = m.passwordInput.CharLimit
+	pw, err := g.generate()
+	if err != nil {
+		log.Printf("generate: %v", err)
+		return m, nil
+	}
+	m.passwordInput.SetValue(pw)
+	m.confirmInput.SetValue(pw)
+	m.setErr(fieldPassword, "")
+	m.setErr(fieldConfirm, "")
+	if m.showPassword {
+		return m, nil
+	}
+	m.revealedGenerated = true
+	return m.setShowPassword(true), m.tick(generatedRevealTime, func(time.Time) tea.Msg {
+		return generatedHideMsg{}
+	})
+}
+
+// hideGenerated masks the password again, unless the user has toggled
+// the reveal themselves in the meantime.
+func (m model) hideGenerated() model {
+	if !m.revealedGenerated {
+		return m
+	}
+	m.revealedGenerated = false
+	return m.setShowPassword(false)
+}
@@ -0,0 +1,121 @@
+package cornice
+
+import (
+	"bytes"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// seededGenerator returns a generator drawing from a fixed seed, so its
+// passwords are reproducible.
+func seededGenerator(length int, charsets ...string) passwordGenerator {
+	return passwordGenerator{length: length, charsets: charsets, rand: rand.New(rand.NewSource(1))}
+}
+
+func TestGeneratedPasswordCoversCharsets(t *testing.T) {
+	sets := []string{CharsLower, CharsUpper, CharsDigits, CharsSymbols}
+	for _, length := range []int{4, 16, 64} {
+		pw, err := seededGenerator(length, sets...).generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pw) != length {
+			t.Errorf("len(%q) = %d, want %d", pw, len(pw), length)
+		}
+		for _, set := range sets {
+			if !strings.ContainsAny(pw, set) {
+				t.Errorf("%q has no character from %q", pw, set)
+			}
+		}
+		if strings.Trim(pw, strings.Join(sets, "")) != "" {
+			t.Errorf("%q has characters outside the sets", pw)
+		}
+	}
+}
+
+func TestGeneratedPasswordLengthRaised(t *testing.T) {
+	pw, err := seededGenerator(1, CharsLower, CharsDigits, CharsSymbols).generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pw) != 3 {
+		t.Errorf("len(%q) = %d, want it raised to the 3 sets", pw, len(pw))
+	}
+}
+
+func TestGeneratedPasswordDeterministic(t *testing.T) {
+	a, _ := seededGenerator(20, CharsLower, CharsDigits).generate()
+	b, _ := seededGenerator(20, CharsLower, CharsDigits).generate()
+	if a != b {
+		t.Errorf("the same seed gave %q and %q", a, b)
+	}
+}
+
+func TestGenerateShortcut(t *testing.T) {
+	m := initialModel(WithPasswordGenerator(12, CharsLower))
+	g := seededGenerator(12, CharsLower)
+	m.generator = &g
+	m = m.setFocus(1)
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	m = next.(model)
+	if got := len(m.passwordInput.Value()); got != 12 {
+		t.Fatalf("password length = %d after Ctrl+G, want 12", got)
+	}
+	if !m.showPassword {
+		t.Error("the generated password isn't revealed")
+	}
+	m = drive(m, generatedHideMsg{})
+	if m.showPassword {
+		t.Error("the generated password wasn't masked again")
+	}
+}
+
+func TestPasswordGeneratorRejectsBadSettings(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	for _, opt := range []Option{
+		WithPasswordGenerator(0),
+		WithPasswordGenerator(-4, CharsLower),
+		WithPasswordGenerator(12, CharsLower, ""),
+	} {
+		logged.Reset()
+		if m := initialModel(opt); m.generator != nil {
+			t.Errorf("generator set up as %+v", *m.generator)
+		}
+		if logged.Len() == 0 {
+			t.Error("the ignored generator wasn't logged")
+		}
+	}
+}
+
+func TestGeneratedPasswordFitsCharLimit(t *testing.T) {
+	m := initialModel(WithPasswordGenerator(12, CharsLower), WithCharLimits(0, 8))
+	g := seededGenerator(12, CharsLower)
+	m.generator = &g
+	m = m.setFocus(1)
+
+	m = drive(m, tea.KeyMsg{Type: tea.KeyCtrlG})
+	if got := len(m.passwordInput.Value()); got != 8 {
+		t.Errorf("password length = %d, want it capped at the limit of 8", got)
+	}
+}
+
+func TestGeneratedPasswordLimitWinsOverSets(t *testing.T) {
+	g := seededGenerator(8, CharsLower, CharsUpper, CharsDigits)
+	g.limit = 2
+	pw, err := g.generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pw) != 2 {
+		t.Errorf("len(%q) = %d, want the limit of 2", pw, len(pw))
+	}
+}
@@ -0,0 +1,45 @@
+package cornice
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var guestStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#00AFFF")).Italic(true)
+
+// loginAsGuest completes the form with empty credentials, skipping
+// validation and the empty-field confirmation.
+func (m model) loginAsGuest() (tea.Model, tea.Cmd) {
+	m.guest = true
+	for _, f := range m.fields() {
+		if in := m.input(f); in != nil {
+			in.SetValue("")
+		}
+		m.setErr(f, "")
+	}
+	m.submittedAt = m.now()
+	return m.complete()
+}
+
+// guestHint renders the guest shortcut beneath the fields, or "" when guest
+// access isn't offered.
+func (m model) guestHint() string {
+	if !m.guestKey.Enabled() {
+		return ""
+	}
+	return m.style(guestStyle).Render(m.guestKey.Help().Key + ": continue as guest")
+}
+
+// guestHeight is the number of rows the guest hint takes up.
+func (m model) guestHeight() int {
+	if m.guestHint() == "" {
+		return 0
+	}
+	return 1
+}
+
+// newGuestKey binds k to guest access.
+func newGuestKey(k string) key.Binding {
+	return key.NewBinding(key.WithKeys(k), key.WithHelp(k, "continue as guest"))
+}
@@ -0,0 +1,44 @@
+package cornice
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestGuestSkipsValidation(t *testing.T) {
+	m := initialModel(WithGuest("ctrl+o"), WithEmail(), WithPasswordLength(8, 0))
+	m = drive(m, script(keys("not-an-email"), []tea.Msg{tea.KeyMsg{Type: tea.KeyCtrlO}})...)
+	if !m.done {
+		t.Fatal("guest key did not complete the form")
+	}
+	c := m.credentials()
+	if !c.Guest {
+		t.Error("Credentials.Guest not set")
+	}
+	if c.Username != "" || c.Password != "" {
+		t.Errorf("guest credentials = %q, %q; want them empty", c.Username, c.Password)
+	}
+	for _, f := range m.fields() {
+		if err := m.err(f); err != "" {
+			t.Errorf("%s shows %q after guest access", m.fieldName(f), err)
+		}
+	}
+}
+
+func TestGuestResultOutput(t *testing.T) {
+	c := Credentials{Guest: true}
+	var b bytes.Buffer
+	if err := printJSON(&b, c); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), `"guest":true`) {
+		t.Errorf("JSON %s lacks the guest flag", b.String())
+	}
+	b.Reset()
+	if err := printLines(&b, c); err != nil || b.Len() != 0 {
+		t.Errorf("lines for a guest = %q, %v; want nothing", b.String(), err)
+	}
+}
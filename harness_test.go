@@ -0,0 +1,229 @@
+package cornice
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakeClock is a manually advanced clock for driving timing-dependent
+// behaviour deterministically. Install it with m.now = clock.Now.
+type fakeClock struct {
+	t time.Time
+}
+
+// newFakeClock returns a clock stopped at start.
+func newFakeClock(start time.Time) *fakeClock {
+	return &fakeClock{t: start}
+}
+
+// Now returns the clock's current time.
+func (c *fakeClock) Now() time.Time {
+	return c.t
+}
+
+// Advance moves the clock forward by d.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+// typeInto focuses f and types text into it one rune at a time through
+// Update, advancing clock by perKey before each keystroke. Commands
+// returned along the way are discarded.
+func typeInto(m model, f field, text string, perKey time.Duration, clock *fakeClock) model {
+	m.now = clock.Now
+	if i, ok := m.fieldIndexOf(f); ok {
+		m = m.setFocus(i)
+	}
+	for _, r := range text {
+		clock.Advance(perKey)
+		next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = next.(model)
+	}
+	return m
+}
+
+const (
+	// driveCmdTimeout is how long drive waits for a command before
+	// failing. Driven forms have no timers, so only a hung command hits it.
+	driveCmdTimeout = 5 * time.Second
+
+	// driveMaxMsgs stops drive from following self-renewing commands
+	// forever.
+	driveMaxMsgs = 1000
+)
+
+// withoutTimers stops m's timers and cursor blink, so every command it
+// returns completes straight away.
+func withoutTimers(m model) model {
+	m.tick = func(time.Duration, func(time.Time) tea.Msg) tea.Cmd { return nil }
+	m.extras = append([]extraField(nil), m.extras...)
+	for _, f := range m.fields() {
+		if in := m.input(f); in != nil {
+			in.Cursor.SetMode(cursor.CursorStatic)
+		}
+	}
+	return m
+}
+
+// drive feeds msgs through Update in order, running the commands each
+// step returns to completion and feeding their messages back in before
+// the next one, and returns the final model. It stops early on tea.Quit.
+// Timers, the cursor blink and spinner frames are switched off, so
+// timing-dependent messages must be passed in explicitly.
+func drive(m model, msgs ...tea.Msg) model {
+	processed := 0
+	for len(msgs) > 0 && processed < driveMaxMsgs {
+		msg := msgs[0]
+		msgs = msgs[1:]
+		processed++
+		switch msg := msg.(type) {
+		case nil, spinner.TickMsg:
+			continue
+		case tea.QuitMsg:
+			return m
+		case tea.BatchMsg:
+			var results []tea.Msg
+			for _, cmd := range msg {
+				results = append(results, runCmd(cmd)...)
+			}
+			msgs = append(results, msgs...)
+			continue
+		}
+		next, cmd := withoutTimers(m).Update(msg)
+		m = next.(model)
+		msgs = append(runCmd(cmd), msgs...)
+	}
+	return m
+}
+
+// runCmd runs cmd to completion and returns its message, if any. A
+// command still running after driveCmdTimeout fails the test run, rather
+// than being dropped and leaving the test to fail somewhere less obvious.
+func runCmd(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd() }()
+	select {
+	case msg := <-done:
+		if msg == nil {
+			return nil
+		}
+		return []tea.Msg{msg}
+	case <-time.After(driveCmdTimeout):
+		panic(fmt.Sprintf("drive: command still running after %v", driveCmdTimeout))
+	}
+}
+
+// TestMain keeps the tests away from the user's saved state and
+// environment.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "cornice-test")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	os.Setenv("HOME", dir)
+	os.Unsetenv("CORNICE_USERNAME")
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// keys returns the keystrokes typing s.
+func keys(s string) []tea.Msg {
+	var msgs []tea.Msg
+	for _, r := range s {
+		msgs = append(msgs, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return msgs
+}
+
+// script joins groups of messages into one script for drive.
+func script(groups ...[]tea.Msg) []tea.Msg {
+	var msgs []tea.Msg
+	for _, g := range groups {
+		msgs = append(msgs, g...)
+	}
+	return msgs
+}
+
+func TestDriveFeedsCommandResultsBack(t *testing.T) {
+	var got string
+	m := initialModel(WithSubmit(func(username, password string) error {
+		got = username + ":" + password
+		return nil
+	}))
+	m = drive(m, script(keys("alice"), []tea.Msg{tea.KeyMsg{Type: tea.KeyTab}}, keys("secret"), []tea.Msg{tea.KeyMsg{Type: tea.KeyEnter}})...)
+	if got != "alice:secret" {
+		t.Errorf("submit called with %q, want %q", got, "alice:secret")
+	}
+	if m.submitting || !m.done {
+		t.Errorf("submitting = %v, done = %v; want the submit result applied", m.submitting, m.done)
+	}
+}
+
+func TestDriveStopsOnQuit(t *testing.T) {
+	m := drive(initialModel(), script([]tea.Msg{tea.KeyMsg{Type: tea.KeyEsc}}, keys("ignored"))...)
+	if v := m.usernameInput.Value(); v != "" {
+		t.Errorf("username = %q after quit, want the later keys dropped", v)
+	}
+}
+
+func TestDriveExpandsBatches(t *testing.T) {
+	typed := func(r rune) tea.Cmd {
+		return func() tea.Msg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}} }
+	}
+	m := drive(initialModel(), tea.BatchMsg{typed('a'), nil, typed('b')}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	if v := m.usernameInput.Value(); v != "abc" {
+		t.Errorf("username = %q, want %q", v, "abc")
+	}
+}
+
+func TestDriveWaitsForSlowCommands(t *testing.T) {
+	slow := func() tea.Msg {
+		time.Sleep(50 * time.Millisecond)
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}}
+	}
+	m := drive(initialModel(), tea.BatchMsg{slow})
+	if v := m.usernameInput.Value(); v != "x" {
+		t.Errorf("username = %q, want the slow command's message delivered", v)
+	}
+}
+
+func TestDriveSkipsTimers(t *testing.T) {
+	start := time.Now()
+	m := drive(initialModel(WithLastCharReveal(time.Minute), WithIdleTimeout(time.Minute)),
+		script(keys("alice"), []tea.Msg{tea.KeyMsg{Type: tea.KeyTab}}, keys("secret"))...)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("drive took %s, want timers and the cursor blink skipped", elapsed)
+	}
+	if v := m.passwordInput.Value(); v != "secret" {
+		t.Errorf("password = %q, want %q", v, "secret")
+	}
+}
+
+func TestTypeIntoAdvancesClockPerKey(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := newFakeClock(start)
+	m := typeInto(initialModel(), fieldPassword, "abc", 100*time.Millisecond, clock)
+	if v := m.passwordInput.Value(); v != "abc" {
+		t.Errorf("password = %q, want %q", v, "abc")
+	}
+	if m.focusedField() != fieldPassword {
+		t.Errorf("focused %v, want the password", m.focusedField())
+	}
+	if got, want := clock.Now(), start.Add(300*time.Millisecond); !got.Equal(want) {
+		t.Errorf("clock at %s, want %s", got, want)
+	}
+	if got, want := m.firstKeyAt, start.Add(100*time.Millisecond); !got.Equal(want) {
+		t.Errorf("first key at %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,23 @@
+package cornice
+
+import "github.com/charmbracelet/lipgloss"
+
+// headerView renders the form title, centered over the boxes, or "" when
+// no title is set.
+func (m model) headerView() string {
+	if m.title == "" {
+		return ""
+	}
+	return m.theme.Title.
+		Width(m.boxWidth() + 2).
+		Align(lipgloss.Center).
+		Render(m.title)
+}
+
+// headerHeight is the number of rows the header takes up above the fields.
+func (m model) headerHeight() int {
+	if m.title == "" {
+		return 0
+	}
+	return lipgloss.Height(m.headerView())
+}
@@ -0,0 +1,62 @@
+package cornice
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestHeaderShiftsBoxes(t *testing.T) {
+	t.Setenv("TERM", "xterm-256color")
+	size := tea.WindowSizeMsg{Width: 80, Height: 30}
+	plain := drive(initialModel(WithAlignment(lipgloss.Left, lipgloss.Top), WithColorMode(ColorNever)), size)
+	titled := drive(initialModel(WithAlignment(lipgloss.Left, lipgloss.Top), WithColorMode(ColorNever), WithTitle("Acme VPN Login")), size)
+
+	if strings.Contains(plain.View(), "Acme") || plain.headerHeight() != 0 {
+		t.Error("an empty title takes up room")
+	}
+	if plain.headerView() != "" {
+		t.Errorf("an empty title renders %q", plain.headerView())
+	}
+	view := titled.View()
+	if !strings.Contains(view, "Acme VPN Login") {
+		t.Fatalf("the view lacks the title:\n%s", view)
+	}
+	if strings.Index(view, "Acme VPN Login") > strings.Index(view, "Username") {
+		t.Error("the title isn't above the fields")
+	}
+
+	// The username box moves down by the header's height, and clicks
+	// follow it.
+	shift := titled.headerHeight()
+	if shift == 0 {
+		t.Fatal("the header has no height")
+	}
+	lines := strings.Split(view, "\n")
+	plainLines := strings.Split(plain.View(), "\n")
+	if row(lines, "Username") != row(plainLines, "Username")+shift {
+		t.Errorf("the username title is on row %d, want %d", row(lines, "Username"), row(plainLines, "Username")+shift)
+	}
+	y := passwordRow(titled)
+	if passwordRow(plain)+shift != y {
+		t.Errorf("password row = %d with the header, want %d", y, passwordRow(plain)+shift)
+	}
+	if got := drive(titled, click(2, y)); got.focusedField() != fieldPassword {
+		t.Errorf("click on the shifted password box focused %v", got.focusedField())
+	}
+	if got := drive(titled, tea.KeyMsg{Type: tea.KeyTab}, click(2, 0)); got.focusedField() != fieldPassword {
+		t.Errorf("click on the header moved focus to %v", got.focusedField())
+	}
+}
+
+// row returns the index of the first line containing s, or -1.
+func row(lines []string, s string) int {
+	for i, l := range lines {
+		if strings.Contains(l, s) {
+			return i
+		}
+	}
+	return -1
+}
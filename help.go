@@ -0,0 +1,88 @@
+package cornice
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpKey toggles between the short and full help.
+const helpKey = "?"
+
+// keyMap describes the form's bindings for the help footer. The bindings
+// themselves are handled in Update.
+type keyMap struct {
+	next, prev, submit, reveal, generate, paste, copy, mouse, guest, help, quit key.Binding
+}
+
+// keyMap returns the bindings as currently configured.
+func (m model) keyMap() keyMap {
+	helpDesc := "more"
+	if m.showFullHelp {
+		helpDesc = "less"
+	}
+	k := keyMap{
+		next:     key.NewBinding(key.WithKeys("tab", "down"), key.WithHelp("tab", "next field")),
+		prev:     key.NewBinding(key.WithKeys("shift+tab", "up"), key.WithHelp("shift+tab", "previous field")),
+		submit:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "submit")),
+		reveal:   key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "reveal password")),
+		generate: key.NewBinding(key.WithKeys(generateKey), key.WithHelp(generateKey, "generate password")),
+		paste:    key.NewBinding(key.WithKeys(pasteKey), key.WithHelp(pasteKey, "paste")),
+		copy:     key.NewBinding(key.WithKeys(copyKey), key.WithHelp(copyKey, "copy username")),
+		mouse:    key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("ctrl+t", "toggle mouse")),
+		guest:    m.guestKey,
+		help:     key.NewBinding(key.WithKeys(helpKey), key.WithHelp(helpKey, helpDesc)),
+		quit:     key.NewBinding(key.WithKeys("esc", "ctrl+c"), key.WithHelp("esc", "quit")),
+	}
+	if m.enterAdvances {
+		k.next = key.NewBinding(key.WithKeys("tab", "down", "enter"), key.WithHelp("tab/enter", "next field"))
+		k.submit = key.NewBinding(key.WithKeys("ctrl+s", "ctrl+d"), key.WithHelp("ctrl+s", "submit"))
+	}
+	if m.literalTab {
+		// Tab is typed into the field.
+		k.next = key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "next field"))
+	}
+	k.generate.SetEnabled(m.generator != nil)
+	k.copy.SetEnabled(m.supportsOSC52())
+	k.mouse.SetEnabled(m.mouseAvailable())
+	return k
+}
+
+// ShortHelp implements help.KeyMap.
+func (k keyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.next, k.submit, k.help, k.quit}
+}
+
+// FullHelp implements help.KeyMap. Two columns fit the default box width.
+func (k keyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.next, k.prev, k.submit, k.quit},
+		{k.reveal, k.generate, k.paste, k.copy, k.mouse, k.guest, k.help},
+	}
+}
+
+// toggleHelp reports whether "?" should switch the help rather than be
+// typed: only on an empty field, so it can still be part of a value, and
+// never on a secret one, where it may start a password.
+func (m model) toggleHelp() bool {
+	f := m.focusedField()
+	return m.showHelp && !m.fieldSecret(f) && m.fieldValue(f) == ""
+}
+
+// helpView renders the help footer, or "" when it is hidden.
+func (m model) helpView() string {
+	if !m.showHelp {
+		return ""
+	}
+	h := m.help
+	h.ShowAll = m.showFullHelp
+	h.Width = m.boxWidth() + 2
+	return h.View(m.keyMap())
+}
+
+// helpHeight is the number of rows the help footer takes up.
+func (m model) helpHeight() int {
+	if !m.showHelp {
+		return 0
+	}
+	return lipgloss.Height(m.helpView())
+}
@@ -0,0 +1,53 @@
+package cornice
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var question = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}}
+
+func TestHelpToggleChangesHeight(t *testing.T) {
+	m := drive(initialModel(), tea.WindowSizeMsg{Width: 80, Height: 24})
+	short := m.helpHeight()
+	m = drive(m, question)
+	if !m.showFullHelp {
+		t.Fatal("? did not expand the help")
+	}
+	if full := m.helpHeight(); full <= short {
+		t.Errorf("help height %d expanded, %d collapsed; want it taller", full, short)
+	}
+	m = drive(m, question)
+	if got := m.helpHeight(); got != short {
+		t.Errorf("help height %d after collapsing, want %d", got, short)
+	}
+}
+
+func TestHelpQuestionMarkIsTypedIntoValues(t *testing.T) {
+	m := drive(initialModel(), keys("a?b")...)
+	if v := m.usernameInput.Value(); v != "a?b" {
+		t.Errorf("username = %q, want %q", v, "a?b")
+	}
+	if m.showFullHelp {
+		t.Error("? in a value expanded the help")
+	}
+
+	m = drive(initialModel(), tea.KeyMsg{Type: tea.KeyTab}, question)
+	if v := m.passwordInput.Value(); v != "?" {
+		t.Errorf("password = %q, want %q", v, "?")
+	}
+	if m.showFullHelp {
+		t.Error("? on the password expanded the help")
+	}
+}
+
+func TestHelpHidden(t *testing.T) {
+	m := drive(initialModel(WithHelp(false)), tea.WindowSizeMsg{Width: 80, Height: 24}, question)
+	if m.helpHeight() != 0 || m.helpView() != "" {
+		t.Error("help shown with WithHelp(false)")
+	}
+	if v := m.usernameInput.Value(); v != "?" {
+		t.Errorf("username = %q, want ? typed with the help off", v)
+	}
+}
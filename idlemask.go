@@ -0,0 +1,42 @@
+package cornice
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// idleMaskMsg asks the model to check whether the revealed password has
+// been idle long enough to be masked again.
+type idleMaskMsg struct{}
+
+func (m model) idleMaskAfter(d time.Duration) tea.Cmd {
+	return m.tick(d, func(time.Time) tea.Msg {
+		return idleMaskMsg{}
+	})
+}
+
+// scheduleIdleMask starts the inactivity check if the password is revealed
+// and none is pending.
+func (m model) scheduleIdleMask() (model, tea.Cmd) {
+	if m.maskAfter <= 0 || !m.showPassword || m.idleMaskPending {
+		return m, nil
+	}
+	m.idleMaskPending = true
+	return m, m.idleMaskAfter(m.maskAfter)
+}
+
+// handleIdleMask masks the password once maskAfter has passed since the
+// last activity, or re-arms the check for the remaining time.
+func (m model) handleIdleMask() (tea.Model, tea.Cmd) {
+	m.idleMaskPending = false
+	if m.maskAfter <= 0 || !m.showPassword {
+		return m, nil
+	}
+	idle := m.now().Sub(m.lastActivity)
+	if idle >= m.maskAfter {
+		return m.setShowPassword(false), nil
+	}
+	m.idleMaskPending = true
+	return m, m.idleMaskAfter(m.maskAfter - idle)
+}
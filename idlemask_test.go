@@ -0,0 +1,64 @@
+package cornice
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// revealed returns m with the password revealed through Ctrl+R, and the
+// idle check that starts.
+func revealed(t *testing.T, m model) (model, tea.Cmd) {
+	t.Helper()
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = next.(model)
+	if !m.showPassword || !m.idleMaskPending {
+		t.Fatalf("show = %v, pending = %v after Ctrl+R", m.showPassword, m.idleMaskPending)
+	}
+	return m, cmd
+}
+
+func TestIdleMaskMasksAfterInactivity(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := initialModel(WithMaskAfter(time.Minute))
+	m.now = clock.Now
+	m, _ = revealed(t, m)
+
+	clock.Advance(30 * time.Second)
+	next, cmd := m.Update(idleMaskMsg{})
+	m = next.(model)
+	if !m.showPassword || cmd == nil {
+		t.Fatalf("masked after 30s, or not re-armed: show = %v, cmd = %v", m.showPassword, cmd != nil)
+	}
+
+	clock.Advance(30 * time.Second)
+	next, _ = m.Update(idleMaskMsg{})
+	m = next.(model)
+	if m.showPassword || m.idleMaskPending {
+		t.Errorf("show = %v, pending = %v after a minute idle, want masked", m.showPassword, m.idleMaskPending)
+	}
+}
+
+func TestIdleMaskDuringLockout(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := initialModel(
+		WithMaskAfter(time.Minute),
+		WithRetryLimit(1, time.Hour),
+		WithSubmit(func(string, string) error { return errors.New("no") }),
+	)
+	m.now = clock.Now
+	m, _ = revealed(t, m)
+	m = drive(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.locked() {
+		t.Fatal("form not locked after the failed submit")
+	}
+
+	clock.Advance(time.Minute)
+	next, _ := m.Update(idleMaskMsg{})
+	m = next.(model)
+	if m.showPassword || m.idleMaskPending {
+		t.Errorf("show = %v, pending = %v after the tick during lockout, want masked", m.showPassword, m.idleMaskPending)
+	}
+}
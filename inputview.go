@@ -0,0 +1,135 @@
+package cornice
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	chevronLeft  = "‹"
+	chevronRight = "›"
+)
+
+// inputView renders in within width terminal cells. Values too long to fit are
+// windowed around the cursor, with chevrons marking hidden content on
+// either side. When invalid is set, the runes it reports are highlighted.
+// A masked value keeps its last revealLast runes visible. The highlight and
+// chevrons are drawn with r.
+func inputView(r *lipgloss.Renderer, in textinput.Model, width int, invalid func(rune) bool, revealLast int) string {
+	value := []rune(in.Value())
+	prompt := in.PromptStyle.Render(in.Prompt)
+	avail := width - lipgloss.Width(prompt)
+	partial := revealLast > 0 && in.EchoMode == textinput.EchoPassword
+	if len(value) == 0 || in.EchoMode == textinput.EchoNone {
+		return in.View()
+	}
+
+	// Work in terminal cells rather than runes, so wide characters such
+	// as CJK or emoji don't push the border out of line.
+	cells := make([]int, len(value))
+	total := 0
+	for i, r := range value {
+		cells[i] = runewidth.StringWidth(echoRune(in, r))
+		total += cells[i]
+	}
+	overflow := total+1 > avail
+	if !overflow && invalid == nil && !partial && !strings.ContainsRune(in.Value(), tabRune) {
+		return in.View()
+	}
+
+	pos := in.Position()
+	start, end := 0, len(value)
+	inner := avail
+	if overflow {
+		inner = avail - 2
+		if inner < 1 {
+			inner = 1
+		}
+		// Scroll right until the cursor's cell fits, then take as much
+		// after it as fits.
+		cursor := 1
+		if pos < len(value) {
+			cursor = cells[pos]
+		}
+		used := cursor
+		for i := 0; i < pos; i++ {
+			used += cells[i]
+		}
+		for used > inner && start < pos {
+			used -= cells[start]
+			start++
+		}
+		used = 0
+		end = start
+		for end < len(value) && used+cells[end] <= inner {
+			used += cells[end]
+			end++
+		}
+	}
+	var b strings.Builder
+	b.WriteString(prompt)
+	if overflow {
+		b.WriteString(chevron(r, start > 0, chevronLeft))
+	}
+	for i := start; i < end; i++ {
+		style := in.TextStyle
+		if invalid != nil && invalid(value[i]) {
+			style = invalidStyle.Renderer(r)
+		}
+		char := echoRune(in, value[i])
+		if i >= len(value)-revealLast {
+			char = displayTabs(string(value[i]))
+		}
+		if i == pos {
+			c := in.Cursor
+			c.TextStyle = style
+			c.SetChar(char)
+			b.WriteString(c.View())
+			continue
+		}
+		b.WriteString(style.Inline(true).Render(char))
+	}
+	if pos >= end {
+		c := in.Cursor
+		c.SetChar(" ")
+		b.WriteString(c.View())
+	}
+	if overflow {
+		// Pad out a cell left over when a wide character didn't fit.
+		shown := 0
+		for i := start; i < end; i++ {
+			shown += cells[i]
+		}
+		if pos >= end {
+			shown++
+		}
+		if shown < inner {
+			b.WriteString(strings.Repeat(" ", inner-shown))
+		}
+		b.WriteString(chevron(r, end < len(value), chevronRight))
+	}
+	return b.String()
+}
+
+// echoRune returns how r is displayed under in's echo mode.
+func echoRune(in textinput.Model, r rune) string {
+	if in.EchoMode == textinput.EchoPassword {
+		return string(in.EchoCharacter)
+	}
+	if r == tabRune {
+		r = tabSymbol
+	}
+	return string(r)
+}
+
+// chevron returns glyph when content is hidden on that side, or a blank
+// of the same width so the layout doesn't shift.
+func chevron(r *lipgloss.Renderer, hidden bool, glyph string) string {
+	if !hidden {
+		return " "
+	}
+	return previewStyle.Renderer(r).Render(glyph)
+}
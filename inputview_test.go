@@ -0,0 +1,92 @@
+package cornice
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const alphabet = "abcdefghijklmnopqrstuvwxyz"
+
+// longInput returns a focused input holding value with the cursor at pos.
+func longInput(value string, pos int) textinput.Model {
+	in := textinput.New()
+	in.Focus()
+	in.SetValue(value)
+	in.SetCursor(pos)
+	return in
+}
+
+func TestInputViewScrollsWithChevrons(t *testing.T) {
+	const width = 12
+	tests := []struct {
+		name        string
+		pos         int
+		left, right bool
+		visible     string
+	}{
+		{"cursor at end", len(alphabet), true, false, "z"},
+		{"cursor at start", 0, false, true, "a"},
+		{"cursor in the middle", 13, true, true, "n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view := inputView(nil, longInput(alphabet, tt.pos), width, nil, 0)
+			if w := lipgloss.Width(view); w != width {
+				t.Errorf("view %q is %d cells wide, want %d", view, w, width)
+			}
+			if got := strings.Contains(view, chevronLeft); got != tt.left {
+				t.Errorf("view %q: left chevron %v, want %v", view, got, tt.left)
+			}
+			if got := strings.Contains(view, chevronRight); got != tt.right {
+				t.Errorf("view %q: right chevron %v, want %v", view, got, tt.right)
+			}
+			if !strings.Contains(view, tt.visible) {
+				t.Errorf("view %q hides %q under the cursor", view, tt.visible)
+			}
+		})
+	}
+}
+
+func TestInputViewFitsWithoutChevrons(t *testing.T) {
+	view := inputView(nil, longInput("short", 5), 20, nil, 0)
+	if strings.Contains(view, chevronLeft) || strings.Contains(view, chevronRight) {
+		t.Errorf("view %q has chevrons for a value that fits", view)
+	}
+}
+
+func TestInputViewWideCharacters(t *testing.T) {
+	const width = 12
+	for _, value := range []string{"山田太郎", strings.Repeat("山田", 10), "ユーザー🙂"} {
+		for _, pos := range []int{0, len([]rune(value))} {
+			view := inputView(nil, longInput(value, pos), width, nil, 0)
+			scrolled := strings.Contains(view, chevronLeft) || strings.Contains(view, chevronRight)
+			if w := lipgloss.Width(view); w > width || (scrolled && w != width) {
+				t.Errorf("value %q, cursor %d: view %q is %d cells wide, want %d", value, pos, view, w, width)
+			}
+		}
+	}
+}
+
+func TestWideUsernameKeepsBordersAligned(t *testing.T) {
+	m := initialModel()
+	m = drive(m, tea.WindowSizeMsg{Width: 80, Height: 24})
+	m = drive(m, paste("山田太郎"))
+
+	boxes := m.fieldBoxes()
+	want := lipgloss.Width(boxes[1])
+	for _, line := range strings.Split(boxes[0], "\n") {
+		if w := lipgloss.Width(line); w != want {
+			t.Errorf("username box line %q is %d cells wide, want %d like the password box", line, w, want)
+		}
+	}
+
+	m = drive(m, tea.KeyMsg{Type: tea.KeyEnter})
+	line := strings.Split(m.resultView(), "\n")[0]
+	if got, want := lipgloss.Width(line), len("Username: ")+8; got != want {
+		t.Errorf("result line %q is %d columns, want %d", line, got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package cornice
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultLastCharReveal is how long a typed secret character stays
+// visible with WithLastCharReveal(0).
+const defaultLastCharReveal = 500 * time.Millisecond
+
+// lastCharHideMsg masks the character revealed with the same seq again.
+// Ticks for earlier keystrokes are ignored.
+type lastCharHideMsg struct{ seq int }
+
+// revealLastChar shows the character just typed into the secret field f
+// until the reveal time passes or another key is pressed. Nothing is
+// shown unless it was typed at the end of the value, as only the last
+// character can be kept unmasked.
+func (m model) revealLastChar(f field, key tea.KeyMsg, before string) (model, tea.Cmd) {
+	m.lastCharShown = false
+	if m.lastCharReveal == 0 || !m.fieldSecret(f) || key.Type != tea.KeyRunes || key.Paste {
+		return m, nil
+	}
+	in := m.input(f)
+	if in.Value() == before || in.Position() != len([]rune(in.Value())) {
+		return m, nil
+	}
+	m.lastCharShown = true
+	m.lastCharSeq++
+	seq := m.lastCharSeq
+	return m, m.tick(m.lastCharReveal, func(time.Time) tea.Msg {
+		return lastCharHideMsg{seq: seq}
+	})
+}
+
+// hideLastChar masks the revealed character if msg belongs to the latest
+// keystroke.
+func (m model) hideLastChar(msg lastCharHideMsg) model {
+	if msg.seq == m.lastCharSeq {
+		m.lastCharShown = false
+	}
+	return m
+}
+
+// revealCount is how many trailing characters of the secret field f are
+// left unmasked.
+func (m model) revealCount(f field) int {
+	if m.lastCharShown && f == m.focusedField() && m.revealLastN < 1 {
+		return 1
+	}
+	return m.revealLastN
+}
@@ -0,0 +1,36 @@
+package cornice
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLastCharRevealedUntilTick(t *testing.T) {
+	m := initialModel(WithLastCharReveal(time.Second))
+	m = drive(m, tea.WindowSizeMsg{Width: 80, Height: 24}, tea.KeyMsg{Type: tea.KeyTab})
+	m = drive(m, keys("ab")...)
+	if v := m.View(); !strings.Contains(v, "> *b") {
+		t.Fatalf("the last character isn't shown alone right after typing it:\n%s", v)
+	}
+
+	stale := lastCharHideMsg{seq: m.lastCharSeq - 1}
+	m = drive(m, stale)
+	if !strings.Contains(m.View(), "> *b") {
+		t.Error("the tick of an earlier keystroke masked the latest one")
+	}
+	m = drive(m, lastCharHideMsg{seq: m.lastCharSeq})
+	if v := m.View(); !strings.Contains(v, "> **") {
+		t.Errorf("the last character is still shown after its tick:\n%s", v)
+	}
+}
+
+func TestLastCharRevealSkipsPaste(t *testing.T) {
+	m := initialModel(WithLastCharReveal(time.Second))
+	m = drive(m, tea.WindowSizeMsg{Width: 80, Height: 24}, tea.KeyMsg{Type: tea.KeyTab}, paste("secret"))
+	if m.lastCharShown || !strings.Contains(m.View(), "> ******") {
+		t.Error("a pasted character was revealed")
+	}
+}
@@ -0,0 +1,115 @@
+package cornice
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// formView renders everything placed on screen: the title, fields, guest
+// hint, submit error, warnings, error footer, status bar and key help.
+// Terminals too short for that get the compact layout.
+func (m model) formView() string {
+	if m.locked() {
+		return m.lockoutView()
+	}
+	if m.compact() {
+		return m.compactView()
+	}
+	form := m.scrolledForm()
+	if header := m.headerView(); header != "" {
+		form = lipgloss.JoinVertical(lipgloss.Left, header, form)
+	}
+	if hint := m.guestHint(); hint != "" {
+		form = lipgloss.JoinVertical(lipgloss.Left, form, hint)
+	}
+	if err := m.submitErrLine(); err != "" {
+		form = lipgloss.JoinVertical(lipgloss.Left, form, err)
+	}
+	if warning := m.warningLine(); warning != "" {
+		form = lipgloss.JoinVertical(lipgloss.Left, form, warning)
+	}
+	if footer := m.footer(); footer != "" {
+		form = lipgloss.JoinVertical(lipgloss.Left, form, footer)
+	}
+	if status := m.statusBar(); status != "" {
+		form = lipgloss.JoinVertical(lipgloss.Left, form, status)
+	}
+	if help := m.helpView(); help != "" {
+		form = lipgloss.JoinVertical(lipgloss.Left, form, help)
+	}
+	return form
+}
+
+// belowFieldsHeight is the number of rows between the fields and the
+// status bar.
+func (m model) belowFieldsHeight() int {
+	return m.guestHeight() + m.submitErrHeight() + m.warningHeight() + m.footerHeight()
+}
+
+// formPosition is where the form is placed in the terminal, centered
+// unless WithAlignment says otherwise.
+func (m model) formPosition() (h, v lipgloss.Position) {
+	return m.alignH, m.alignV
+}
+
+// ParseHorizontalPosition parses a placement across: "left", "center" or
+// "right".
+func ParseHorizontalPosition(s string) (lipgloss.Position, error) {
+	switch s {
+	case "left":
+		return lipgloss.Left, nil
+	case "center":
+		return lipgloss.Center, nil
+	case "right":
+		return lipgloss.Right, nil
+	}
+	return lipgloss.Center, fmt.Errorf("unknown horizontal alignment %q, want left, center or right", s)
+}
+
+// ParseVerticalPosition parses a placement down: "top", "center" or
+// "bottom".
+func ParseVerticalPosition(s string) (lipgloss.Position, error) {
+	switch s {
+	case "top":
+		return lipgloss.Top, nil
+	case "center":
+		return lipgloss.Center, nil
+	case "bottom":
+		return lipgloss.Bottom, nil
+	}
+	return lipgloss.Center, fmt.Errorf("unknown vertical alignment %q, want top, center or bottom", s)
+}
+
+// formOrigin returns the screen cell of the form's top-left corner, as
+// laid out by lipgloss.Place in View.
+func (m model) formOrigin() (x, y int) {
+	form := m.formView()
+	h, v := m.formPosition()
+	return placeOffset(m.width, lipgloss.Width(form), h), placeOffset(m.height, lipgloss.Height(form), v)
+}
+
+// placeOffset mirrors lipgloss.Place: the leading gap when content of the
+// given size is positioned at pos within total cells.
+func placeOffset(total, content int, pos lipgloss.Position) int {
+	gap := total - content
+	if gap <= 0 {
+		return 0
+	}
+	switch pos {
+	case lipgloss.Left:
+		return 0
+	case lipgloss.Right:
+		return gap
+	}
+	return gap - int(math.Round(float64(gap)*float64(pos)))
+}
+
+// availableHeight is the number of rows the form may occupy.
+func (m model) availableHeight() int {
+	if m.maxFormHeight > 0 && (m.height <= 0 || m.maxFormHeight < m.height) {
+		return m.maxFormHeight
+	}
+	return m.height
+}
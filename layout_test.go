@@ -0,0 +1,146 @@
+package cornice
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// click returns a left click at x, y.
+func click(x, y int) tea.MouseMsg {
+	return tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft}
+}
+
+// fieldRow is the terminal row of the middle of the box of the field at
+// index i.
+func fieldRow(m model, i int) int {
+	_, oy := m.formOrigin()
+	tops := fieldTops(m.fieldBoxes())
+	return oy + m.headerHeight() + (tops[i]+tops[i+1])/2
+}
+
+// passwordRow is the terminal row of the middle of the password box.
+func passwordRow(m model) int {
+	return fieldRow(m, 1)
+}
+
+func TestClickOutsideBoxesKeepsFocus(t *testing.T) {
+	t.Setenv("TERM", "xterm-256color")
+	m := initialModel(WithAlignment(lipgloss.Left, lipgloss.Top))
+	m = drive(m, tea.WindowSizeMsg{Width: 200, Height: 40})
+	y := passwordRow(m)
+	right := m.boxWidth() + 2
+
+	for _, x := range []int{right, right + 50, 199} {
+		if got := drive(m, click(x, y)); got.focusedField() != fieldUsername {
+			t.Errorf("click at x=%d, right of the boxes, focused %v", x, got.focusedField())
+		}
+	}
+	if got := drive(m, click(right-1, y)); got.focusedField() != fieldPassword {
+		t.Errorf("click on the password box's right border focused %v, want the password", got.focusedField())
+	}
+}
+
+func TestCenteredFormOffsets(t *testing.T) {
+	t.Setenv("TERM", "xterm-256color")
+	m := drive(initialModel(), tea.WindowSizeMsg{Width: 120, Height: 40})
+	ox, oy := m.formOrigin()
+	formWidth := lipgloss.Width(m.formView())
+	if want := (120 - formWidth) / 2; ox != want || ox == 0 {
+		t.Errorf("left padding = %d, want %d for a centered form", ox, want)
+	}
+	if oy == 0 {
+		t.Error("a centered form has no top padding")
+	}
+
+	// Clicks use the same offsets: just inside the password box hits it,
+	// just left of it hits nothing.
+	y := passwordRow(m)
+	if got := drive(m, click(ox, y)); got.focusedField() != fieldPassword {
+		t.Errorf("click at the centered box's left edge focused %v, want the password", got.focusedField())
+	}
+	if got := drive(m, click(ox-1, y)); got.focusedField() != fieldUsername {
+		t.Errorf("click left of the centered box focused %v", got.focusedField())
+	}
+}
+
+func TestPlaceOffset(t *testing.T) {
+	tests := []struct {
+		total, content int
+		pos            lipgloss.Position
+		want           int
+	}{
+		{100, 40, lipgloss.Left, 0},
+		{100, 40, lipgloss.Center, 30},
+		{100, 40, lipgloss.Right, 60},
+		{100, 41, lipgloss.Center, 29},
+		{40, 100, lipgloss.Center, 0},
+	}
+	for _, tt := range tests {
+		if got := placeOffset(tt.total, tt.content, tt.pos); got != tt.want {
+			t.Errorf("placeOffset(%d, %d, %v) = %d, want %d", tt.total, tt.content, tt.pos, got, tt.want)
+		}
+	}
+}
+
+func TestBoxSizeBounds(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []Option
+		width        int
+		wantBox      int
+		wantRendered int
+	}{
+		{"default max", nil, 200, 50, 52},
+		{"custom max", []Option{WithBoxSize(0, 30, 0, 0)}, 200, 30, 32},
+		{"custom min", []Option{WithBoxSize(40, 0, 0, 0)}, 30, 40, 42},
+		{"fits the terminal", []Option{WithBoxSize(10, 60, 0, 0)}, 44, 40, 42},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := drive(initialModel(append(tt.opts, WithColorMode(ColorNever))...), tea.WindowSizeMsg{Width: tt.width, Height: 40})
+			if got := m.boxWidth(); got != tt.wantBox {
+				t.Errorf("boxWidth() = %d, want %d", got, tt.wantBox)
+			}
+			if got := lipgloss.Width(m.fieldBoxes()[0]); got != tt.wantRendered {
+				t.Errorf("rendered box is %d wide, want %d", got, tt.wantRendered)
+			}
+		})
+	}
+}
+
+func TestBoxSizeIsPerForm(t *testing.T) {
+	size := tea.WindowSizeMsg{Width: 200, Height: 40}
+	narrow := drive(initialModel(WithBoxSize(0, 25, 0, 0)), size)
+	wide := drive(initialModel(WithBoxSize(0, 70, 0, 4)), size)
+	if narrow.boxWidth() != 25 || wide.boxWidth() != 70 {
+		t.Errorf("box widths = %d and %d, want 25 and 70 side by side", narrow.boxWidth(), wide.boxWidth())
+	}
+	if wide.boxHeight() != 4 {
+		t.Errorf("boxHeight() = %d, want the custom max 4", wide.boxHeight())
+	}
+}
+
+func TestParsePositionPerAxis(t *testing.T) {
+	for s, want := range map[string]lipgloss.Position{"left": lipgloss.Left, "center": lipgloss.Center, "right": lipgloss.Right} {
+		if got, err := ParseHorizontalPosition(s); err != nil || got != want {
+			t.Errorf("ParseHorizontalPosition(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	for s, want := range map[string]lipgloss.Position{"top": lipgloss.Top, "center": lipgloss.Center, "bottom": lipgloss.Bottom} {
+		if got, err := ParseVerticalPosition(s); err != nil || got != want {
+			t.Errorf("ParseVerticalPosition(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"top", "bottom", "middle"} {
+		if _, err := ParseHorizontalPosition(s); err == nil {
+			t.Errorf("ParseHorizontalPosition accepted %q", s)
+		}
+	}
+	for _, s := range []string{"left", "right", "middle"} {
+		if _, err := ParseVerticalPosition(s); err == nil {
+			t.Errorf("ParseVerticalPosition accepted %q", s)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package cornice
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// tabRune stores a literal tab in a text input, which replaces tabs
+	// with a space as they are inserted. It is a Unicode noncharacter,
+	// reserved for internal use, so it can't clash with typed text; values
+	// are handed out with it turned back into a tab.
+	tabRune = '\uFDD0'
+
+	// tabSymbol is how a literal tab is displayed.
+	tabSymbol = '␉'
+)
+
+// literalTabKey turns Tab, and tabs in a paste, into tabRune keystrokes
+// while a text input has focus. A pasted tabRune is dropped, so only a
+// real tab becomes one. On the remember checkbox and the forgot password
+// action Tab still moves focus.
+func (m model) literalTabKey(msg tea.KeyMsg) tea.KeyMsg {
+	if !m.literalTab || m.input(m.focusedField()) == nil {
+		return msg
+	}
+	switch msg.Type {
+	case tea.KeyTab:
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{tabRune}}
+	case tea.KeyRunes:
+		runes := make([]rune, 0, len(msg.Runes))
+		for _, r := range msg.Runes {
+			switch r {
+			case tabRune:
+				continue
+			case '\t':
+				r = tabRune
+			}
+			runes = append(runes, r)
+		}
+		msg.Runes = runes
+	}
+	return msg
+}
+
+// untab restores the literal tabs in s, a value typed with WithLiteralTab.
+// Every value handed to a callback or validator goes through it.
+func (m model) untab(s string) string {
+	if !m.literalTab {
+		return s
+	}
+	return strings.ReplaceAll(s, string(tabRune), "\t")
+}
+
+// displayTabs shows the literal tabs in s as tabSymbol.
+func displayTabs(s string) string {
+	return strings.ReplaceAll(s, string(tabRune), string(tabSymbol))
+}
+
+// retab is the reverse of untab, for a value put back into an input.
+func (m model) retab(s string) string {
+	if !m.literalTab {
+		return s
+	}
+	return strings.ReplaceAll(s, "\t", string(tabRune))
+}
@@ -0,0 +1,96 @@
+package cornice
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var tab = tea.KeyMsg{Type: tea.KeyTab}
+
+func TestTabMovesFocusByDefault(t *testing.T) {
+	m := drive(initialModel(), script(keys("al"), []tea.Msg{tab}, keys("pw"))...)
+	if m.focusedField() != fieldPassword {
+		t.Errorf("focused %v after Tab, want the password", m.focusedField())
+	}
+	if c := m.credentials(); c.Username != "al" || c.Password != "pw" {
+		t.Errorf("got %q/%q, want al/pw", c.Username, c.Password)
+	}
+}
+
+func TestLiteralTabInsertsTab(t *testing.T) {
+	m := initialModel(WithLiteralTab())
+	m = drive(m, script(
+		keys("al"), []tea.Msg{tab}, keys("ice"),
+		[]tea.Msg{tea.KeyMsg{Type: tea.KeyDown}},
+		keys("a"), []tea.Msg{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b\tc"), Paste: true}},
+	)...)
+	if m.focusedField() != fieldPassword {
+		t.Errorf("focused %v after Down, want the password", m.focusedField())
+	}
+	c := m.credentials()
+	if c.Username != "al\tice" {
+		t.Errorf("username = %q, want a literal tab typed in it", c.Username)
+	}
+	if c.Password != "ab\tc" {
+		t.Errorf("password = %q, want the pasted tab kept", c.Password)
+	}
+}
+
+func TestLiteralTabNavigatesOffInputs(t *testing.T) {
+	m := initialModel(WithLiteralTab(), WithRememberMe(), WithTOTP())
+	i, _ := m.fieldIndexOf(fieldRemember)
+	m = m.setFocus(i)
+	want := m.moveFocus(1).focused
+	if m = drive(m, tab); m.focused != want {
+		t.Errorf("focused index %d after Tab on the checkbox, want %d", m.focused, want)
+	}
+}
+
+func TestLiteralTabReachesCallbacksAndValidators(t *testing.T) {
+	var got string
+	m := initialModel(WithLiteralTab(), WithRegister(), WithPasswordLength(3, 3),
+		WithConfirmValidator(func(v string) error {
+			if v != "a\tb" {
+				t.Errorf("confirm validator got %q, want a real tab", v)
+			}
+			return nil
+		}),
+		WithSubmit(func(username, password string) error {
+			got = password
+			return nil
+		}))
+	m = m.setFocus(1)
+	m = drive(m, script(keys("a"), []tea.Msg{tab}, keys("b"))...)
+	if err := m.fieldError(fieldPassword); err != "" {
+		t.Errorf("a 3-character password with a tab failed the length check: %s", err)
+	}
+	m = drive(m, script([]tea.Msg{tea.KeyMsg{Type: tea.KeyDown}}, keys("a"), []tea.Msg{tab}, keys("b"))...)
+	if err := m.fieldError(fieldConfirm); err != "" {
+		t.Errorf("confirm error = %q, want it to match the password", err)
+	}
+	drive(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if got != "a\tb" {
+		t.Errorf("submit callback got %q, want a real tab", got)
+	}
+}
+
+func TestLiteralTabKeepsTabSymbolTyped(t *testing.T) {
+	m := drive(initialModel(WithLiteralTab()), keys("a␉b")...)
+	if c := m.credentials(); c.Username != "a␉b" {
+		t.Errorf("username = %q, want a typed ␉ kept as it is", c.Username)
+	}
+	m = drive(initialModel(WithLiteralTab()), paste("a\uFDD0b"))
+	if c := m.credentials(); c.Username != "ab" {
+		t.Errorf("username = %q, want a pasted placeholder dropped", c.Username)
+	}
+}
+
+func TestLiteralTabDisplayed(t *testing.T) {
+	m := initialModel(WithLiteralTab(), WithColorMode(ColorNever))
+	m = drive(m, script([]tea.Msg{tea.WindowSizeMsg{Width: 80, Height: 24}}, keys("a"), []tea.Msg{tab}, keys("b"))...)
+	if v := m.View(); !strings.Contains(v, "a␉b") || strings.ContainsRune(v, tabRune) {
+		t.Errorf("the tab isn't shown as ␉:\n%s", v)
+	}
+}
@@ -0,0 +1,77 @@
+package cornice
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// lockoutTick is how often the lockout countdown is updated.
+const lockoutTick = time.Second
+
+// lockoutTickMsg counts the lockout down by one tick.
+type lockoutTickMsg struct{}
+
+func (m model) nextLockoutTick() tea.Cmd {
+	return m.tick(lockoutTick, func(time.Time) tea.Msg {
+		return lockoutTickMsg{}
+	})
+}
+
+// locked reports whether the form is locked after too many failures.
+func (m model) locked() bool {
+	return m.lockRemaining > 0
+}
+
+// recordFailure counts a rejected submit, locking the form for the
+// cooldown once maxAttempts is reached.
+func (m model) recordFailure() (model, tea.Cmd) {
+	m.failedAttempts++
+	if m.maxAttempts <= 0 || m.lockout <= 0 || m.failedAttempts < m.maxAttempts {
+		return m, nil
+	}
+	m.lockRemaining = m.lockout
+	return m, m.nextLockoutTick()
+}
+
+// recordSuccess clears the failure count and any lockout, so failures
+// before a successful login never count towards a later lockout.
+func (m model) recordSuccess() model {
+	m.failedAttempts = 0
+	m.lockRemaining = 0
+	return m
+}
+
+// updateLocked handles messages while the form is locked. Input is
+// ignored except Ctrl+C; the form unlocks, with a fresh attempt count,
+// when the countdown ends.
+func (m model) updateLocked(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case lockoutTickMsg:
+		m.lockRemaining -= lockoutTick
+		if m.lockRemaining <= 0 {
+			m.lockRemaining = 0
+			m.failedAttempts = 0
+			m.submitErr = ""
+			return m, nil
+		}
+		return m, m.nextLockoutTick()
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+	case tea.WindowSizeMsg:
+		m = m.resize(msg)
+	}
+	return m, nil
+}
+
+// lockoutView replaces the fields while the form is locked.
+func (m model) lockoutView() string {
+	secs := int((m.lockRemaining + time.Second - 1) / time.Second)
+	msg := fmt.Sprintf("Too many attempts. Try again in %ds.", secs)
+	return m.theme.Blurred.
+		Width(m.boxWidth()).
+		Render(m.style(errorStyle).Render(msg))
+}
@@ -0,0 +1,81 @@
+package cornice
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLockoutCountResetsOnSuccess(t *testing.T) {
+	results := []error{errors.New("wrong"), errors.New("wrong"), nil, errors.New("wrong")}
+	calls := 0
+	m := initialModel(
+		WithRetryLimit(3, time.Minute),
+		WithSubmit(func(username, password string) error {
+			err := results[calls]
+			calls++
+			return err
+		}),
+	)
+	login := script(keys("alice"), []tea.Msg{tea.KeyMsg{Type: tea.KeyTab}}, keys("secret"))
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+
+	m = drive(m, append(login, enter, enter)...)
+	if m.failedAttempts != 2 || m.locked() {
+		t.Fatalf("after two failures: failedAttempts = %d, locked = %v; want 2, unlocked", m.failedAttempts, m.locked())
+	}
+	m = drive(m, enter)
+	if !m.done || m.failedAttempts != 0 {
+		t.Fatalf("after the success: done = %v, failedAttempts = %d; want done with the count cleared", m.done, m.failedAttempts)
+	}
+	// A third failure in a row would lock the form; after the success it
+	// is only the first.
+	m = drive(m, append([]tea.Msg{resetMsg{}}, append(login, enter)...)...)
+	if calls != 4 {
+		t.Fatalf("submit called %d times, want 4", calls)
+	}
+	if m.failedAttempts != 1 || m.locked() {
+		t.Errorf("after the last failure: failedAttempts = %d, locked = %v; want 1, unlocked", m.failedAttempts, m.locked())
+	}
+}
+
+func TestLockoutAfterThreeFailures(t *testing.T) {
+	calls := 0
+	m := initialModel(
+		WithColorMode(ColorNever),
+		WithRetryLimit(3, 2*time.Second),
+		WithSubmit(func(username, password string) error {
+			calls++
+			return errors.New("wrong")
+		}),
+	)
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	m = drive(m, script(
+		[]tea.Msg{tea.WindowSizeMsg{Width: 80, Height: 24}},
+		keys("alice"), []tea.Msg{tea.KeyMsg{Type: tea.KeyTab}}, keys("secret"),
+		[]tea.Msg{enter, enter, enter},
+	)...)
+	if !m.locked() || calls != 3 {
+		t.Fatalf("locked = %v after %d failures, want locked after 3", m.locked(), calls)
+	}
+	if view := m.View(); !strings.Contains(view, "Too many attempts. Try again in 2s.") {
+		t.Errorf("the locked view lacks the countdown:\n%s", view)
+	}
+
+	m = drive(m, enter)
+	if calls != 3 {
+		t.Error("Enter submitted while locked")
+	}
+
+	m = drive(m, lockoutTickMsg{})
+	if !strings.Contains(m.View(), "Try again in 1s.") {
+		t.Error("the countdown didn't tick down")
+	}
+	m = drive(m, lockoutTickMsg{})
+	if m.locked() || m.failedAttempts != 0 {
+		t.Errorf("locked = %v, failedAttempts = %d after the cooldown; want unlocked with a fresh count", m.locked(), m.failedAttempts)
+	}
+}
@@ -0,0 +1,22 @@
+package cornice
+
+import "time"
+
+// markFirstKey records the time of the first keystroke.
+func (m model) markFirstKey() model {
+	if m.firstKeyAt.IsZero() {
+		m.firstKeyAt = m.now()
+	}
+	return m
+}
+
+// timeToLogin returns how long the user took to submit, measured from
+// program start or, when timeFromFirstKey is set, from the first keystroke
+// so that idle time before typing is excluded.
+func (m model) timeToLogin() time.Duration {
+	start := m.startedAt
+	if m.timeFromFirstKey && !m.firstKeyAt.IsZero() {
+		start = m.firstKeyAt
+	}
+	return m.submittedAt.Sub(start)
+}
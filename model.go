@@ -0,0 +1,1009 @@
+package cornice
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+var dimStyle = lipgloss.NewStyle().Faint(true)
+
+// defaultEchoCharacter masks secret values; plain ASCII renders on every
+// terminal.
+const defaultEchoCharacter = '*'
+
+// boxBounds limits the size of each field's box, borders excluded: its
+// width follows the terminal and its height the rows available per field.
+type boxBounds struct {
+	minWidth, maxWidth   int
+	minHeight, maxHeight int
+}
+
+var defaultBoxBounds = boxBounds{minWidth: 20, maxWidth: 50, minHeight: 3, maxHeight: 5}
+
+type model struct {
+	usernameInput textinput.Model
+	passwordInput textinput.Model
+	confirmInput  textinput.Model
+	totpInput     textinput.Model
+	focused       int
+	done          bool
+	width         int
+	height        int
+
+	// maxFields caps how many fields are shown before the form scrolls;
+	// zero means as many as fit in the terminal.
+	maxFields int
+	viewport  viewport.Model
+
+	// usernameChars restricts the characters accepted in the username;
+	// empty allows any. Disallowed characters are dropped while typing,
+	// unless highlightInvalid is set, in which case they are kept,
+	// highlighted, and rejected on submit.
+	usernameChars    string
+	highlightInvalid bool
+
+	// usernameTaken checks whether a username is already registered. When
+	// set, the username is checked as it is typed, a taken one blocks
+	// submit, and a free alternative is offered.
+	usernameTaken func(string) bool
+	availability  availabilityMsg
+
+	// usernameEditedAt is when the username last changed, for debouncing
+	// the availability check.
+	usernameEditedAt time.Time
+
+	// usernameTransform is applied to the username on submit, with a live
+	// preview beneath the field.
+	usernameTransform func(string) string
+
+	// usernameLength and passwordLength bound each value's length, checked
+	// on submit and, when validateOnBlur is set, whenever a field loses
+	// focus.
+	usernameLength lengthPolicy
+	passwordLength lengthPolicy
+
+	// showStrength shows a live strength meter beneath the password.
+	showStrength bool
+
+	// totp asks for a one-time code after the password.
+	totp bool
+
+	// email asks for an email address in place of the username, checked
+	// for a plausible format on submit.
+	email bool
+
+	// usernamePattern, when set, must match the whole username on submit.
+	usernamePattern *regexp.Regexp
+
+	validateOnBlur bool
+	errorPlacement ErrorPlacement
+
+	// validationBorders colors each border by whether its field's value
+	// currently passes validation.
+	validationBorders bool
+
+	// onFocusChange is called with the names of the fields losing and
+	// gaining focus whenever focus moves.
+	onFocusChange func(from, to string)
+
+	// attemptedSubmit is set once a submit has been blocked, from then on
+	// highlighting the empty fields that need a value.
+	attemptedSubmit bool
+
+	// readClipboard reads the system clipboard for Ctrl+V.
+	readClipboard func() (string, error)
+
+	// offerRemember shows a checkbox that, when ticked, stores the
+	// username to prefill on the next launch.
+	offerRemember bool
+	remember      bool
+
+	// escapedAt is when Esc last cleared a field; see escape.
+	escapedAt time.Time
+
+	// capsLock is set while Caps Lock appears to be on; see trackCapsLock.
+	capsLock bool
+
+	// errs holds the error shown for each built-in field, indexed by
+	// field. extras are the fields added with AddField.
+	errs   [numFields]string
+	extras []extraField
+
+	// register adds a confirm field that must match the password. The
+	// confirm value is also checked by confirmValidate, when set.
+	register        bool
+	confirmValidate func(string) error
+
+	// forgotPassword, when set, adds a "Forgot password?" action below
+	// the fields that calls it when activated, instead of submitting.
+	forgotPassword func()
+
+	// quitOnSubmit ends the program as soon as the form is completed.
+	// Otherwise the result stays on screen until Ctrl+C, or until any key
+	// when dismissOnKey is set.
+	quitOnSubmit bool
+	dismissOnKey bool
+
+	// onSubmit, when set, checks the credentials on submit. It runs in
+	// the background while submitting shows the spinner; an error keeps
+	// the form open, showing submitErr.
+	onSubmit   func(username, password string) error
+	submitting bool
+	spinner    spinner.Model
+	submitErr  string
+
+	// maxAttempts rejected submits lock the form for lockout, counting
+	// down in lockRemaining. Zero maxAttempts never locks.
+	maxAttempts    int
+	lockout        time.Duration
+	failedAttempts int
+	lockRemaining  time.Duration
+
+	// passwordSummary is how the result screen describes the password.
+	// With resultMode set to anything but resultText there is no result
+	// screen; Run prints the credentials instead.
+	passwordSummary PasswordSummary
+	resultMode      resultMode
+
+	// showPassword reveals the password as it is typed. It is toggled
+	// with Ctrl+R, and remembered across sessions when persistReveal is
+	// set. forceMask ignores the remembered choice at startup.
+	showPassword  bool
+	persistReveal bool
+	forceMask     bool
+
+	// generator, when set, fills the password on Ctrl+G. revealedGenerated
+	// is set while the generated password is briefly shown.
+	generator         *passwordGenerator
+	revealedGenerated bool
+
+	// echoCharacter masks the characters of every secret field.
+	echoCharacter rune
+
+	// revealLastN keeps the last N characters of masked values visible.
+	revealLastN int
+
+	// lastCharReveal, when set, shows each character typed into a masked
+	// field for that long. lastCharShown is set while it is visible;
+	// lastCharSeq tells the latest keystroke from earlier ones.
+	lastCharReveal time.Duration
+	lastCharShown  bool
+	lastCharSeq    int
+
+	// maskAfter re-masks a revealed password after this much inactivity;
+	// zero disables it.
+	maskAfter       time.Duration
+	lastActivity    time.Time
+	idleMaskPending bool
+
+	// idleTimeout quits the form after this much inactivity, setting
+	// timedOut; zero disables it.
+	idleTimeout time.Duration
+	timedOut    bool
+
+	// now is the clock used for timing, and tick schedules the form's
+	// timers; tests may replace them.
+	now              func() time.Time
+	tick             func(time.Duration, func(time.Time) tea.Msg) tea.Cmd
+	startedAt        time.Time
+	firstKeyAt       time.Time
+	submittedAt      time.Time
+	timeFromFirstKey bool
+
+	// maskOnTerminalBlur masks the password and dims the form while the
+	// terminal window is unfocused.
+	maskOnTerminalBlur bool
+	terminalBlurred    bool
+
+	// guestKey, when enabled, completes the form with empty credentials
+	// and sets guest, bypassing validation.
+	guestKey key.Binding
+	guest    bool
+
+	// confirmEmpty asks before submitting with blank fields, and review
+	// shows a summary to confirm before completing.
+	confirmEmpty bool
+	review       bool
+	confirming   *confirmation
+
+	// animateSubmit flashes the borders green before completing.
+	animateSubmit bool
+	animating     bool
+	animFrame     int
+
+	// maxFormWidth and maxFormHeight cap the form's footprint, borders
+	// included; zero leaves that dimension uncapped.
+	maxFormWidth  int
+	maxFormHeight int
+	bounds        boxBounds
+
+	// alignH and alignV place the form within the terminal.
+	alignH, alignV lipgloss.Position
+
+	// title is shown as a header above the fields when set.
+	title string
+
+	// inline renders the form in place below the existing terminal
+	// output instead of filling and clearing the screen.
+	inline bool
+
+	// pasted is set while the latest input came from a bracketed paste.
+	pasted bool
+
+	// showStatusBar renders a status line beneath the form, with each
+	// field's validity and the focused field's name.
+	showStatusBar bool
+
+	// bell rings the terminal bell and errorFlash briefly lights the
+	// offending field's border when a submit is blocked by validation.
+	// errorFlashing is set while flashField is lit; flashSeq tells the
+	// latest flash from older ones still ticking.
+	bell          bool
+	errorFlash    bool
+	errorFlashing bool
+	flashField    int
+	flashSeq      int
+
+	// enterAdvances makes Enter move to the next field like Tab, leaving
+	// Ctrl+S or Ctrl+D to submit.
+	enterAdvances bool
+
+	// literalTab makes Tab insert a tab into the focused input; see
+	// literalTabKey.
+	literalTab bool
+
+	// initialFocus, when initialFocusSet, is the index of the field the
+	// form starts on, overriding the move to the first empty field.
+	initialFocus    int
+	initialFocusSet bool
+
+	// vimKeys moves the focus with j and k while the focused field is
+	// empty; otherwise they are typed as usual.
+	vimKeys bool
+
+	// mouseEnabled is toggled with Ctrl+T so the terminal's own text
+	// selection can be used mid-session.
+	mouseEnabled bool
+
+	// showHelp renders the key help beneath the form; "?" toggles
+	// showFullHelp to expand it.
+	help         help.Model
+	showHelp     bool
+	showFullHelp bool
+
+	// theme is resolved and bound to renderer, the form's own, once the
+	// options are applied. focusedColor and blurredColor, when set,
+	// override the theme's text colors.
+	theme        Theme
+	focusedText  lipgloss.Style
+	blurredText  lipgloss.Style
+	focusedColor string
+	blurredColor string
+	renderer     *lipgloss.Renderer
+
+	run runConfig
+}
+
+func initialModel(opts ...Option) model {
+	usernameInput := textinput.New()
+	usernameInput.Placeholder = "Enter username"
+	usernameInput.Focus()
+
+	passwordInput := textinput.New()
+	passwordInput.Placeholder = "Enter password"
+	passwordInput.EchoMode = textinput.EchoPassword
+	passwordInput.EchoCharacter = defaultEchoCharacter
+
+	confirmInput := textinput.New()
+	confirmInput.Placeholder = "Re-enter password"
+	confirmInput.EchoMode = textinput.EchoPassword
+	confirmInput.EchoCharacter = defaultEchoCharacter
+
+	m := model{
+		usernameInput: usernameInput,
+		passwordInput: passwordInput,
+		confirmInput:  confirmInput,
+		totpInput:     newTOTPInput(),
+		focused:       0,
+		viewport:      viewport.New(0, 0),
+		spinner:       spinner.New(spinner.WithSpinner(spinner.Dot)),
+		now:           time.Now,
+		tick:          tea.Tick,
+		readClipboard: clipboard.ReadAll,
+		mouseEnabled:  true,
+		bounds:        defaultBoxBounds,
+		echoCharacter: defaultEchoCharacter,
+		help:          help.New(),
+		showHelp:      true,
+		alignH:        lipgloss.Center,
+		alignV:        lipgloss.Center,
+		guestKey:      key.NewBinding(key.WithDisabled()),
+		theme:         DefaultTheme(),
+		// Anything drawn before bindRenderer is redrawn after it.
+		renderer: lipgloss.NewRenderer(io.Discard),
+	}
+	if username := os.Getenv("CORNICE_USERNAME"); username != "" {
+		m.usernameInput.SetValue(username)
+		m = m.setFocus(1)
+	}
+	for _, opt := range opts {
+		opt(&m)
+	}
+	m = m.bindRenderer()
+	// Picking the starting field isn't a move the user made, so neither
+	// the focus callback nor blur validation runs for it.
+	onFocusChange, validateOnBlur := m.onFocusChange, m.validateOnBlur
+	m.onFocusChange, m.validateOnBlur = nil, false
+	// The state file is only read when asked for, so an embedding app
+	// never picks up preferences saved by another.
+	var saved state
+	if m.persistReveal || m.offerRemember {
+		saved = loadState()
+	}
+	if m.persistReveal && !m.forceMask {
+		m = m.setShowPassword(saved.ShowPassword)
+	}
+	if m.offerRemember && saved.Username != "" && m.usernameInput.Value() == "" {
+		m.usernameInput.SetValue(m.retab(saved.Username))
+		m.remember = true
+		m = m.setFocus(1)
+	}
+	if m.initialFocusSet {
+		m = m.focusInitial(m.initialFocus)
+	} else {
+		m = m.focusFirstEmpty()
+	}
+	m.onFocusChange, m.validateOnBlur = onFocusChange, validateOnBlur
+	m.startedAt = m.now()
+	m.lastActivity = m.startedAt
+	return m
+}
+
+func (m model) Init() tea.Cmd {
+	cmds := []tea.Cmd{textinput.Blink}
+	if m.width == 0 || m.height == 0 {
+		cmds = append(cmds, m.probeSizeAfter(sizeProbeDelay))
+	}
+	if m.maskAfter > 0 && m.showPassword {
+		cmds = append(cmds, m.idleMaskAfter(m.maskAfter))
+	}
+	if m.idleTimeout > 0 {
+		cmds = append(cmds, m.idleTimeoutAfter(m.idleTimeout))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	if m.run.eventLog != nil {
+		if fm, ok := next.(model); ok {
+			logEvent(m.run.eventLog, msg, m, fm)
+		}
+	}
+	return next, cmd
+}
+
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case addFieldMsg:
+		return m.addField(msg.label, msg.placeholder, msg.opts...), nil
+	case resetMsg:
+		return m.reset(), textinput.Blink
+	}
+	if m.done {
+		return m.updateDone(msg)
+	}
+	switch msg := msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg:
+		m.lastActivity = m.now()
+	case idleTimeoutMsg:
+		return m.handleIdleTimeout()
+	case idleMaskMsg:
+		// Handled whatever state the form is in, so the check is never
+		// lost and keeps re-arming.
+		return m.handleIdleMask()
+	case generatedHideMsg:
+		return m.hideGenerated(), nil
+	case errorFlashEndMsg:
+		return m.endErrorFlash(msg), nil
+	case lastCharHideMsg:
+		return m.hideLastChar(msg), nil
+	}
+	if m.animating {
+		return m.updateAnimating(msg)
+	}
+	if m.confirming != nil {
+		return m.updateConfirming(msg)
+	}
+	if m.submitting {
+		return m.updateSubmitting(msg)
+	}
+	if m.locked() {
+		return m.updateLocked(msg)
+	}
+	if key, ok := msg.(tea.KeyMsg); ok && key.String() == pasteKey {
+		var pasted tea.KeyMsg
+		if m, pasted, ok = m.clipboardPaste(); !ok {
+			return m, nil
+		}
+		msg = pasted
+	}
+	if key, ok := msg.(tea.KeyMsg); ok {
+		msg = m.literalTabKey(key)
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m = m.markFirstKey()
+		m.pasted = msg.Paste
+		m.submitErr = ""
+		if key.Matches(msg, m.guestKey) {
+			return m.loginAsGuest()
+		}
+		switch msg.String() {
+		case "ctrl+c":
+			return m.interrupt()
+		case "esc":
+			return m.escape()
+		case "enter":
+			if m.focusedField() == fieldForgot {
+				return m, m.forgot()
+			}
+			if m.enterAdvances {
+				return m.moveFocus(1), nil
+			}
+			return m.submit()
+		case "ctrl+s", "ctrl+d":
+			if m.enterAdvances {
+				return m.submit()
+			}
+		case "up", "shift+tab":
+			return m.moveFocus(-1), nil
+		case "down", "tab":
+			return m.moveFocus(1), nil
+		case "j", "k":
+			if m.vimNavigation() {
+				if msg.String() == "j" {
+					return m.moveFocus(1), nil
+				}
+				return m.moveFocus(-1), nil
+			}
+		case copyKey:
+			return m, m.copyUsername()
+		case generateKey:
+			if m.generator != nil && m.fieldSecret(m.focusedField()) {
+				return m.generatePassword()
+			}
+		case "ctrl+t":
+			if !m.mouseAvailable() {
+				return m, nil
+			}
+			m.mouseEnabled = !m.mouseEnabled
+			if m.mouseEnabled {
+				return m, tea.EnableMouseAllMotion
+			}
+			return m, tea.DisableMouse
+		case helpKey:
+			if m.toggleHelp() {
+				m.showFullHelp = !m.showFullHelp
+				return m.scrollToFocus(), nil
+			}
+		case "ctrl+r":
+			m.revealedGenerated = false
+			m = m.setShowPassword(!m.showPassword)
+			m, cmd = m.scheduleIdleMask()
+			if m.persistReveal {
+				cmd = tea.Batch(cmd, saveShowPasswordCmd(m.showPassword))
+			}
+			return m, cmd
+		}
+	case tea.MouseMsg:
+		if !m.mouseEnabled {
+			return m, nil
+		}
+		if msg.Action == tea.MouseActionPress && m.scrolling() && !m.compact() {
+			switch msg.Button {
+			case tea.MouseButtonWheelUp:
+				return m.scrollBy(-wheelScrollRows), nil
+			case tea.MouseButtonWheelDown:
+				return m.scrollBy(wheelScrollRows), nil
+			}
+		}
+		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+			ox, oy := m.formOrigin()
+			x, y := msg.X-ox, msg.Y-oy
+			if i, ok := m.statusFieldAt(x, y); ok {
+				m = m.setFocus(i)
+			} else if i, ok := m.fieldAt(x, y); ok {
+				m = m.setFocus(i)
+				if m.focusedField() == fieldForgot {
+					return m, m.forgot()
+				}
+			}
+		}
+		return m, nil
+	case tea.WindowSizeMsg:
+		m = m.resize(msg)
+		return m, nil
+	case sizeProbeMsg:
+		if m.width == 0 || m.height == 0 {
+			m.width, m.height = msg.width, msg.height
+			m = m.scrollToFocus()
+		}
+		return m, nil
+	case tea.BlurMsg:
+		if m.maskOnTerminalBlur {
+			m.terminalBlurred = true
+			m = m.applyEchoMode()
+		}
+		return m, nil
+	case tea.FocusMsg:
+		m.terminalBlurred = false
+		m = m.applyEchoMode()
+		return m, nil
+	case availabilityDueMsg:
+		return m, m.handleAvailabilityDue(msg)
+	case availabilityMsg:
+		return m.handleAvailability(msg), nil
+	case controlMsg:
+		return m.handleControl(msg)
+	}
+
+	f := m.focusedField()
+	if f == fieldRemember {
+		return m.updateRemember(msg)
+	}
+	if f == fieldForgot {
+		return m.updateForgot(msg)
+	}
+	if f == fieldUsername {
+		if key, ok := msg.(tea.KeyMsg); ok {
+			m.setErr(fieldUsername, "")
+			if key.Type == tea.KeyRunes && !m.highlightInvalid {
+				if bad := invalidRunes(m.usernameChars, string(key.Runes)); key.Paste && len(bad) > 0 {
+					// A typed character just doesn't appear, but a paste
+					// losing characters needs saying.
+					m.setErr(fieldUsername, invalidCharsError(bad))
+				}
+				key.Runes = filterRunes(m.usernameChars, key.Runes)
+				if len(key.Runes) == 0 {
+					return m, nil
+				}
+				msg = key
+			}
+		}
+		before := m.usernameInput.Value()
+		m.usernameInput, cmd = m.usernameInput.Update(msg)
+		if m.usernameInput.Value() != before {
+			var check tea.Cmd
+			m, check = m.usernameChanged()
+			cmd = tea.Batch(cmd, check)
+		}
+	} else {
+		if key, ok := msg.(tea.KeyMsg); ok {
+			m.setErr(f, "")
+			if m.fieldSecret(f) {
+				m = m.trackCapsLock(key)
+			}
+			if f == fieldTOTP && key.Type == tea.KeyRunes {
+				key.Runes = filterRunes(totpDigits, key.Runes)
+				if len(key.Runes) == 0 {
+					return m, nil
+				}
+				msg = key
+			}
+		}
+		in := m.input(f)
+		before := in.Value()
+		*in, cmd = in.Update(msg)
+		if key, ok := msg.(tea.KeyMsg); ok {
+			var hide tea.Cmd
+			m, hide = m.revealLastChar(f, key, before)
+			cmd = tea.Batch(cmd, hide)
+		}
+		if m.register && in.Value() != before {
+			// Editing either side re-checks the match, so the confirm
+			// error tracks the password live.
+			m.setErr(fieldConfirm, m.liveConfirmError(f))
+		}
+	}
+
+	if _, ok := msg.(tea.KeyMsg); ok && m.scrolling() {
+		m = m.scrollToFocus()
+	}
+	return m, cmd
+}
+
+// submit completes the form unless a field fails validation, in which case
+// the first invalid field is focused. It asks first when confirmEmpty is set
+// and fields are blank, and when review is set.
+func (m model) submit() (tea.Model, tea.Cmd) {
+	first := -1
+	for i, f := range m.fields() {
+		m.setErr(f, m.fieldError(f))
+		if m.err(f) != "" && first < 0 {
+			first = i
+		}
+	}
+	if first >= 0 {
+		// Send the user straight to the first field needing a fix.
+		m.attemptedSubmit = true
+		m, cmd := m.setFocus(first).blockedSubmit(first)
+		return m, cmd
+	}
+	if empty := m.emptyExpectedFields(); m.confirmEmpty && len(empty) > 0 {
+		return m.ask(emptyFieldsQuestion(empty), model.accept), nil
+	}
+	return m.accept()
+}
+
+// finish hands the credentials to the submit callback, if any, and
+// otherwise accepts them straight away.
+func (m model) finish() (tea.Model, tea.Cmd) {
+	if m.onSubmit != nil {
+		m, cmd := m.authenticate()
+		return m.scrollToFocus(), cmd
+	}
+	return m.accepted()
+}
+
+// accepted applies the username transform and completes the form once the
+// submit animation, if any, has played.
+func (m model) accepted() (tea.Model, tea.Cmd) {
+	m.usernameInput.SetValue(m.usernameValue())
+	m.submittedAt = m.now()
+	if m.animateSubmit {
+		m.animating = true
+		m.animFrame = 0
+		return m, m.nextAnimFrame()
+	}
+	return m.complete()
+}
+
+// complete shows the result screen, or quits when quitOnSubmit is set or
+// the result is printed once the program exits.
+func (m model) complete() (tea.Model, tea.Cmd) {
+	m.done = true
+	if m.quitOnSubmit || m.resultMode != resultText {
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// updateDone handles messages once the result screen is shown.
+func (m model) updateDone(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.dismissOnKey || msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+	case tea.WindowSizeMsg:
+		m = m.resize(msg)
+	}
+	return m, nil
+}
+
+// setShowPassword switches the password between revealed and masked.
+func (m model) setShowPassword(show bool) model {
+	m.showPassword = show
+	return m.applyEchoMode()
+}
+
+// applyEchoMode sets every secret field to the current echo mode.
+func (m model) applyEchoMode() model {
+	for _, f := range m.fields() {
+		if m.fieldSecret(f) {
+			m.input(f).EchoMode = m.echoMode()
+		}
+	}
+	m.confirmInput.EchoMode = m.echoMode()
+	return m
+}
+
+// echoMode is the echo mode every secret field must use, so that they
+// reveal and mask together.
+func (m model) echoMode() textinput.EchoMode {
+	if m.showPassword && !m.terminalBlurred {
+		return textinput.EchoNormal
+	}
+	return textinput.EchoPassword
+}
+
+// usernameError returns the validation error for the username, if any.
+func (m model) usernameError() string {
+	if bad := invalidRunes(m.usernameChars, m.usernameValue()); len(bad) > 0 {
+		return invalidCharsError(bad)
+	}
+	title := m.fieldTitle(fieldUsername)
+	if err := m.usernameLength.check(title, m.usernameValue()); err != "" {
+		return err
+	}
+	if m.email {
+		if err := m.emailError(); err != "" {
+			return err
+		}
+	}
+	if m.usernamePattern != nil && !m.usernamePattern.MatchString(m.usernameValue()) {
+		return title + " must match " + m.usernamePattern.String()
+	}
+	if m.isTaken() {
+		return title + " is taken"
+	}
+	return ""
+}
+
+// focusFirstEmpty moves the focus off a prefilled field to the first
+// empty one, if there is any.
+func (m model) focusFirstEmpty() model {
+	if in := m.input(m.focusedField()); in == nil || in.Value() == "" {
+		return m
+	}
+	for i, f := range m.fields() {
+		if in := m.input(f); in != nil && in.Value() == "" {
+			return m.setFocus(i)
+		}
+	}
+	return m
+}
+
+// focusInitial starts the form on the field at index i, clamped to the
+// active fields. Unlike a focus change it neither validates the field
+// left behind nor reports the move.
+func (m model) focusInitial(i int) model {
+	if i < 0 {
+		i = 0
+	}
+	if n := m.fieldCount(); i >= n {
+		i = n - 1
+	}
+	m.focused = i
+	return m.setFocus(i)
+}
+
+// vimNavigation reports whether j and k move the focus rather than being
+// typed: only with vimKeys set and nothing entered in the focused field.
+func (m model) vimNavigation() bool {
+	return m.vimKeys && m.fieldValue(m.focusedField()) == ""
+}
+
+// moveFocus moves the focus delta fields along the focus order, wrapping
+// around at either end.
+func (m model) moveFocus(delta int) model {
+	n := m.fieldCount()
+	return m.setFocus(((m.focused+delta)%n + n) % n)
+}
+
+// setFocus moves the focus to the input at index i.
+func (m model) setFocus(i int) model {
+	fs := m.fields()
+	if m.validateOnBlur && i != m.focused {
+		m.setErr(fs[m.focused], m.fieldError(fs[m.focused]))
+	}
+	if m.onFocusChange != nil && i != m.focused {
+		m.onFocusChange(m.fieldName(fs[m.focused]), m.fieldName(fs[i]))
+	}
+	m.focused = i
+	for j, f := range fs {
+		if m.input(f) == nil {
+			continue
+		}
+		if j == i {
+			m.input(f).Focus()
+		} else {
+			m.input(f).Blur()
+		}
+	}
+	return m.scrollToFocus()
+}
+
+func (m model) boxWidth() int {
+	widest := m.bounds.maxWidth
+	if m.maxFormWidth > 0 {
+		// The cap covers the whole box, borders included.
+		widest = m.maxFormWidth - 2
+	}
+	boxWidth := m.width - 4
+	if boxWidth > widest {
+		boxWidth = widest
+	}
+	if boxWidth < m.bounds.minWidth {
+		boxWidth = m.bounds.minWidth
+	}
+	return boxWidth
+}
+
+func (m model) boxHeight() int {
+	// Each field needs five rows beyond its box: borders, title and spacing.
+	boxHeight := (m.availableHeight() - 5*m.fieldCount()) / m.fieldCount()
+	if boxHeight < m.bounds.minHeight {
+		boxHeight = m.bounds.minHeight
+	}
+	if boxHeight > m.bounds.maxHeight {
+		boxHeight = m.bounds.maxHeight
+	}
+	return boxHeight
+}
+
+func (m model) View() string {
+	if m.done {
+		if m.resultMode != resultText {
+			return ""
+		}
+		if m.guest {
+			return "Continuing as guest\n"
+		}
+		return m.resultView()
+	}
+	if m.confirming != nil {
+		return m.confirmView()
+	}
+
+	form := m.formView()
+	if m.terminalBlurred {
+		form = m.style(dimStyle).Render(form)
+	}
+
+	if m.inline {
+		return form
+	}
+
+	h, v := m.formPosition()
+	return m.renderer.Place(m.width, m.height, h, v, form)
+}
+
+// resultView summarises the submitted values. Secret values are never
+// shown: the password is described as passwordSummary selects, and other
+// secret fields by their length only when the password's is shown too.
+func (m model) resultView() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s\n", m.fieldTitle(fieldUsername), displayTabs(m.usernameInput.Value()))
+	switch m.passwordSummary {
+	case SummaryHidden:
+		b.WriteString("Password: (hidden)\n")
+	case SummaryLength:
+		fmt.Fprintf(&b, "Password length: %d\n", utf8.RuneCountInString(m.passwordInput.Value()))
+	case SummaryStrength:
+		fmt.Fprintf(&b, "Password strength: %s\n", passwordStrength(m.fieldValue(fieldPassword)))
+	}
+	if m.totp {
+		fmt.Fprintf(&b, "%s: %s\n", m.fieldTitle(fieldTOTP), displayTabs(m.totpInput.Value()))
+	}
+	for _, e := range m.extras {
+		switch {
+		case !e.secret:
+			fmt.Fprintf(&b, "%s: %s\n", e.label, displayTabs(e.input.Value()))
+		case m.passwordSummary == SummaryLength:
+			fmt.Fprintf(&b, "%s length: %d\n", e.label, utf8.RuneCountInString(e.input.Value()))
+		case m.passwordSummary != SummaryNone:
+			fmt.Fprintf(&b, "%s: (hidden)\n", e.label)
+		}
+	}
+	return b.String()
+}
+
+// fieldBoxes renders one bordered box per field, in focus order.
+func (m model) fieldBoxes() []string {
+	boxWidth := m.boxWidth()
+	boxHeight := m.boxHeight()
+
+	var invalid func(rune) bool
+	if m.highlightInvalid {
+		invalid = func(r rune) bool { return !allowedRune(m.usernameChars, r) }
+	}
+
+	var boxes []string
+	for i, f := range m.fields() {
+		focused := i == m.focused && !m.submitting
+		style := m.boxStyle(focused)
+		if color, ok := m.validationColor(f); ok {
+			style = style.BorderForeground(color)
+		} else if m.missing(f) {
+			style = style.BorderForeground(missingColor)
+		}
+		if m.errorFlashed(i) {
+			style = style.BorderForeground(invalidColor)
+		}
+		if m.flashing() {
+			style = m.style(successStyle)
+		}
+
+		if f == fieldRemember {
+			boxes = append(boxes, m.rememberView(focused))
+			continue
+		}
+		if f == fieldForgot {
+			boxes = append(boxes, m.forgotView(focused))
+			continue
+		}
+
+		in := m.styledInput(*m.input(f), focused)
+		var view string
+		if f == fieldUsername {
+			view = inputView(m.renderer, in, boxWidth, invalid, 0)
+			if preview := m.usernamePreview(); preview != "" {
+				view += "\n" + preview
+			}
+		} else {
+			reveal := 0
+			if m.fieldSecret(f) {
+				in.EchoMode = m.echoMode()
+				reveal = m.revealCount(f)
+			}
+			view = inputView(m.renderer, in, boxWidth, nil, reveal)
+			if meter := m.strengthMeter(); f == fieldPassword && meter != "" {
+				view += "\n" + meter
+			}
+		}
+		if counter := m.charCounter(f); counter != "" {
+			view += "\n" + counter
+		}
+
+		boxes = append(boxes, style.
+			Width(boxWidth).
+			Height(boxHeight).
+			Render(m.fieldContent(m.boxTitle(f), view, m.err(f))))
+	}
+	return boxes
+}
+
+// boxTitle is the heading of f's box, marking secret fields that are
+// currently revealed.
+func (m model) boxTitle(f field) string {
+	if m.fieldSecret(f) && m.echoMode() == textinput.EchoNormal {
+		return m.fieldTitle(f) + " (visible)"
+	}
+	return m.fieldTitle(f)
+}
+
+// boxStyle returns the border style for a field. Without color support the
+// focused field gets the theme's mono border so focus doesn't rely on color
+// alone.
+func (m model) boxStyle(focused bool) lipgloss.Style {
+	if !focused {
+		return m.theme.Blurred
+	}
+	style := m.theme.Focused
+	if m.renderer.ColorProfile() == termenv.Ascii {
+		style = style.Border(m.theme.MonoFocusBorder)
+	}
+	if m.theme.FocusMarker != "" {
+		border := style.GetBorderStyle()
+		border.TopLeft = m.theme.FocusMarker
+		style = style.BorderStyle(border)
+	}
+	return style
+}
+
+// styledInput applies the focused or blurred text style to a copy of in,
+// drawn with the form's renderer.
+func (m model) styledInput(in textinput.Model, focused bool) textinput.Model {
+	in.PromptStyle = m.style(in.PromptStyle)
+	in.PlaceholderStyle = m.style(in.PlaceholderStyle)
+	in.CompletionStyle = m.style(in.CompletionStyle)
+	in.Cursor.TextStyle = m.style(in.Cursor.TextStyle)
+	in.Cursor.Style = m.style(in.Cursor.Style)
+	if focused {
+		in.TextStyle = m.focusedText
+		in.Cursor.Style = m.focusedText
+	} else {
+		in.TextStyle = m.blurredText
+	}
+	return in
+}
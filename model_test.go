@@ -0,0 +1,207 @@
+package cornice
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRevealTogglesPasswordAndConfirm(t *testing.T) {
+	m := initialModel(WithRegister())
+	for i, want := range []textinput.EchoMode{textinput.EchoNormal, textinput.EchoPassword, textinput.EchoNormal} {
+		m = drive(m, tea.KeyMsg{Type: tea.KeyCtrlR})
+		if m.passwordInput.EchoMode != want || m.confirmInput.EchoMode != want {
+			t.Errorf("toggle %d: password echo %v, confirm echo %v; want both %v",
+				i+1, m.passwordInput.EchoMode, m.confirmInput.EchoMode, want)
+		}
+	}
+}
+
+func TestTerminalBlurMasksPasswordAndConfirm(t *testing.T) {
+	m := initialModel(WithRegister(), WithMaskOnTerminalBlur())
+	m = drive(m, tea.KeyMsg{Type: tea.KeyCtrlR}, tea.BlurMsg{})
+	if m.passwordInput.EchoMode != textinput.EchoPassword || m.confirmInput.EchoMode != textinput.EchoPassword {
+		t.Errorf("password echo %v, confirm echo %v after blur; want both masked",
+			m.passwordInput.EchoMode, m.confirmInput.EchoMode)
+	}
+	m = drive(m, tea.FocusMsg{})
+	if m.passwordInput.EchoMode != textinput.EchoNormal || m.confirmInput.EchoMode != textinput.EchoNormal {
+		t.Errorf("password echo %v, confirm echo %v after focus; want both revealed again",
+			m.passwordInput.EchoMode, m.confirmInput.EchoMode)
+	}
+}
+
+func TestFailedSubmitFocusesFirstError(t *testing.T) {
+	m := initialModel(WithUsernameLength(1, 0))
+	m = drive(m, script(
+		[]tea.Msg{tea.KeyMsg{Type: tea.KeyTab}},
+		keys("secret"),
+		[]tea.Msg{tea.KeyMsg{Type: tea.KeyEnter}},
+	)...)
+	if m.done {
+		t.Fatal("a blank username was submitted")
+	}
+	if m.focusedField() != fieldUsername {
+		t.Errorf("focused %v after the failed submit, want the username", m.focusedField())
+	}
+	if !m.usernameInput.Focused() || m.passwordInput.Focused() {
+		t.Errorf("username focused %v, password focused %v; want only the username",
+			m.usernameInput.Focused(), m.passwordInput.Focused())
+	}
+}
+
+func TestFocusChangeCallback(t *testing.T) {
+	var changes []string
+	m := initialModel(WithFocusChange(func(from, to string) {
+		changes = append(changes, from+">"+to)
+	}))
+	tab := tea.KeyMsg{Type: tea.KeyTab}
+	m = drive(m, tab, tab, tea.KeyMsg{Type: tea.KeyShiftTab})
+	want := []string{"username>password", "password>username", "username>password"}
+	if strings.Join(changes, " ") != strings.Join(want, " ") {
+		t.Errorf("focus changes = %q, want %q", changes, want)
+	}
+
+	changes = nil
+	m = m.setFocus(m.focused)
+	drive(m, keys("x")...)
+	if len(changes) != 0 {
+		t.Errorf("focus changes = %q without focus moving, want none", changes)
+	}
+}
+
+func TestTabAndShiftTabNavigation(t *testing.T) {
+	m := initialModel(WithRegister(), WithTOTP())
+	if m.focusedField() != fieldUsername {
+		t.Fatalf("starts on %v, want the username", m.focusedField())
+	}
+	if got := drive(m, tea.KeyMsg{Type: tea.KeyTab}).focusedField(); got != fieldPassword {
+		t.Errorf("Tab from the username focused %v, want the password", got)
+	}
+	fs := m.fields()
+	if got := drive(m, tea.KeyMsg{Type: tea.KeyShiftTab}).focusedField(); got != fs[len(fs)-1] {
+		t.Errorf("Shift+Tab from the username focused %v, want the last field %v", got, fs[len(fs)-1])
+	}
+
+	// Tab walks every field in order and wraps back around.
+	for i := 1; i <= len(fs); i++ {
+		m = drive(m, tea.KeyMsg{Type: tea.KeyTab})
+		if want := fs[i%len(fs)]; m.focusedField() != want {
+			t.Errorf("Tab %d focused %v, want %v", i, m.focusedField(), want)
+		}
+	}
+}
+
+func TestCtrlRRevealsPassword(t *testing.T) {
+	m := initialModel(WithColorMode(ColorNever))
+	m = drive(m, tea.WindowSizeMsg{Width: 80, Height: 24}, tea.KeyMsg{Type: tea.KeyCtrlR})
+	if m.passwordInput.EchoMode != textinput.EchoNormal {
+		t.Fatalf("echo mode = %v after Ctrl+R, want EchoNormal", m.passwordInput.EchoMode)
+	}
+	if !strings.Contains(m.View(), "Password (visible)") {
+		t.Error("the password title lacks the (visible) suffix while revealed")
+	}
+
+	// Focus changes and resizes keep it revealed.
+	m = drive(m, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyTab}, tea.WindowSizeMsg{Width: 60, Height: 20})
+	if m.passwordInput.EchoMode != textinput.EchoNormal {
+		t.Errorf("echo mode = %v after focus changes and a resize, want it still revealed", m.passwordInput.EchoMode)
+	}
+
+	m = drive(m, tea.KeyMsg{Type: tea.KeyCtrlR})
+	if m.passwordInput.EchoMode != textinput.EchoPassword {
+		t.Errorf("echo mode = %v after the second Ctrl+R, want EchoPassword", m.passwordInput.EchoMode)
+	}
+	if strings.Contains(m.View(), "(visible)") {
+		t.Error("the (visible) suffix remains once masked")
+	}
+}
+
+func TestUsernameFromEnvironment(t *testing.T) {
+	t.Setenv("CORNICE_USERNAME", "alice")
+	m := initialModel()
+	if v := m.usernameInput.Value(); v != "alice" {
+		t.Errorf("username = %q, want it prefilled from CORNICE_USERNAME", v)
+	}
+	if m.focused != 1 {
+		t.Errorf("focused = %d, want the password at 1", m.focused)
+	}
+
+	t.Setenv("CORNICE_USERNAME", "")
+	m = initialModel()
+	if v := m.usernameInput.Value(); v != "" || m.focused != 0 {
+		t.Errorf("username = %q, focused = %d with the variable empty; want blank on the username", v, m.focused)
+	}
+}
+
+func TestVimNavigation(t *testing.T) {
+	m := initialModel(WithVimKeys())
+	m = drive(m, keys("j")...)
+	if m.focusedField() != fieldPassword || m.usernameInput.Value() != "" {
+		t.Fatalf("j on an empty username: focused %v, username %q; want the password focused", m.focusedField(), m.usernameInput.Value())
+	}
+	m = drive(m, keys("k")...)
+	if m.focusedField() != fieldUsername {
+		t.Fatalf("k on an empty password focused %v, want the username", m.focusedField())
+	}
+
+	// Once the field has text, j and k are typed.
+	m = drive(m, keys("ajk")...)
+	if m.focusedField() != fieldUsername || m.usernameInput.Value() != "ajk" {
+		t.Errorf("typing ajk: focused %v, username %q; want ajk typed", m.focusedField(), m.usernameInput.Value())
+	}
+
+	// Without the option they are always typed.
+	m = drive(initialModel(), keys("jk")...)
+	if m.usernameInput.Value() != "jk" {
+		t.Errorf("username = %q, want jk typed without vim keys", m.usernameInput.Value())
+	}
+}
+
+func TestInitialValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		username string
+		password string
+		focus    field
+	}{
+		{"none", nil, "", "", fieldUsername},
+		{"username", []Option{WithUsername("alice")}, "alice", "", fieldPassword},
+		{"password", []Option{WithPassword("secret")}, "", "secret", fieldUsername},
+		{"both", []Option{WithUsername("alice"), WithPassword("secret")}, "alice", "secret", fieldUsername},
+		{"both with a confirmation", []Option{WithRegister(), WithUsername("alice"), WithPassword("secret")}, "alice", "secret", fieldConfirm},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := initialModel(tt.opts...)
+			if u, p := m.usernameInput.Value(), m.passwordInput.Value(); u != tt.username || p != tt.password {
+				t.Errorf("values = %q/%q, want %q/%q", u, p, tt.username, tt.password)
+			}
+			if f := m.focusedField(); f != tt.focus {
+				t.Errorf("focused %v, want %v", f, tt.focus)
+			}
+		})
+	}
+}
+
+func TestEchoCharacter(t *testing.T) {
+	if got := initialModel().passwordInput.EchoCharacter; got != '*' {
+		t.Errorf("default echo character = %q, want '*'", got)
+	}
+
+	m := initialModel(WithRegister(), WithEchoCharacter('•'))
+	m = m.addField("PIN", "", WithFieldSecret())
+	for _, r := range []rune{m.passwordInput.EchoCharacter, m.confirmInput.EchoCharacter, m.extras[0].input.EchoCharacter} {
+		if r != '•' {
+			t.Errorf("echo character = %q, want '•'", r)
+		}
+	}
+
+	m = drive(m.setFocus(1), keys("abc")...)
+	if v := m.passwordInput.View(); !strings.Contains(v, "•••") {
+		t.Errorf("password renders as %q, want it masked with •", v)
+	}
+}
@@ -0,0 +1,103 @@
+package cornice
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// netrcEntry is the login and password .netrc lists for a machine.
+type netrcEntry struct {
+	login    string
+	password string
+}
+
+// netrcPath returns the .netrc to read: $NETRC if set, otherwise the one
+// in the home directory.
+func netrcPath() (string, error) {
+	if path := os.Getenv("NETRC"); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".netrc"), nil
+}
+
+// parseNetrc finds the entry for host in the contents of a .netrc file,
+// falling back to the default entry. Macro definitions are skipped.
+func parseNetrc(data, host string) (netrcEntry, bool) {
+	var (
+		entry, fallback netrcEntry
+		found, hasDef   bool
+		current         *netrcEntry
+	)
+	lines := strings.Split(data, "\n")
+	for i := 0; i < len(lines); i++ {
+		fields := strings.Fields(lines[i])
+		for j := 0; j < len(fields); j++ {
+			next := func() string {
+				if j+1 < len(fields) {
+					j++
+					return fields[j]
+				}
+				return ""
+			}
+			switch fields[j] {
+			case "machine":
+				current = nil
+				if next() == host && !found {
+					found = true
+					current = &entry
+				}
+			case "default":
+				current = nil
+				if !hasDef {
+					hasDef = true
+					current = &fallback
+				}
+			case "login":
+				if v := next(); current != nil {
+					current.login = v
+				}
+			case "password":
+				if v := next(); current != nil {
+					current.password = v
+				}
+			case "account":
+				next()
+			case "macdef":
+				// A macro runs to the next blank line.
+				for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" {
+					i++
+				}
+				j = len(fields)
+			}
+		}
+	}
+	if found {
+		return entry, true
+	}
+	return fallback, hasDef
+}
+
+// lookupNetrc returns the .netrc entry for host. A missing file or host
+// yields false; other read errors are logged.
+func lookupNetrc(host string) (netrcEntry, bool) {
+	path, err := netrcPath()
+	if err != nil {
+		return netrcEntry{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("netrc: %v", err)
+		}
+		return netrcEntry{}, false
+	}
+	return parseNetrc(string(data), host)
+}
@@ -0,0 +1,58 @@
+package cornice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withNetrc points the form at a .netrc file holding data.
+func withNetrc(t *testing.T, data string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "netrc")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("NETRC", path)
+}
+
+func TestParseNetrc(t *testing.T) {
+	data := "machine a login alice password pa\n" +
+		"macdef init\ncd /\n\n" +
+		"default login anon password guest\n"
+	if e, ok := parseNetrc(data, "a"); !ok || e.login != "alice" || e.password != "pa" {
+		t.Errorf("entry for a = %+v, %v", e, ok)
+	}
+	if e, ok := parseNetrc(data, "b"); !ok || e.login != "anon" {
+		t.Errorf("entry for b = %+v, %v; want the default", e, ok)
+	}
+	if _, ok := parseNetrc("machine a login alice\n", "b"); ok {
+		t.Error("found an entry for a host that isn't listed")
+	}
+}
+
+func TestNetrcFocusesFirstEmptyField(t *testing.T) {
+	withNetrc(t, "machine example.com login alice password secret\n")
+
+	m := initialModel(WithNetrc("example.com", false))
+	if m.usernameInput.Value() != "alice" || m.focused != 1 {
+		t.Errorf("username %q, focus %d; want alice and the password", m.usernameInput.Value(), m.focused)
+	}
+	m = initialModel(WithNetrc("example.com", true))
+	if m.passwordInput.Value() != "secret" {
+		t.Errorf("password = %q, want it from .netrc", m.passwordInput.Value())
+	}
+}
+
+func TestNetrcDoesNotFireFocusChange(t *testing.T) {
+	withNetrc(t, "machine example.com login alice\n")
+
+	var calls int
+	initialModel(
+		WithFocusChange(func(string, string) { calls++ }),
+		WithNetrc("example.com", false),
+	)
+	if calls != 0 {
+		t.Errorf("focus change fired %d times while building the form", calls)
+	}
+}
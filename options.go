@@ -0,0 +1,650 @@
+package cornice
+
+import (
+	"crypto/rand"
+	"io"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Option configures the form.
+type Option func(*model)
+
+// WithMaxFields caps how many fields are shown before the form scrolls.
+func WithMaxFields(n int) Option {
+	return func(m *model) {
+		m.maxFields = n
+	}
+}
+
+// WithMaxFormSize caps the form's overall size, borders included. A zero
+// dimension is left uncapped; the width defaults to a 50-column box.
+func WithMaxFormSize(width, height int) Option {
+	return func(m *model) {
+		m.maxFormWidth = width
+		m.maxFormHeight = height
+	}
+}
+
+// WithBoxSize bounds each field's box, borders excluded: its width
+// between minWidth and maxWidth columns and its height between minHeight
+// and maxHeight rows. A zero keeps that bound's default of 20, 50, 3 and
+// 5 respectively.
+func WithBoxSize(minWidth, maxWidth, minHeight, maxHeight int) Option {
+	return func(m *model) {
+		if minWidth > 0 {
+			m.bounds.minWidth = minWidth
+		}
+		if maxWidth > 0 {
+			m.bounds.maxWidth = maxWidth
+		}
+		if minHeight > 0 {
+			m.bounds.minHeight = minHeight
+		}
+		if maxHeight > 0 {
+			m.bounds.maxHeight = maxHeight
+		}
+	}
+}
+
+// WithAlignment places the form in the terminal: h is lipgloss.Left,
+// Center or Right, v is lipgloss.Top, Center or Bottom. The form is
+// centered both ways by default.
+func WithAlignment(h, v lipgloss.Position) Option {
+	return func(m *model) {
+		m.alignH, m.alignV = h, v
+	}
+}
+
+// WithTitle shows title as a header above the fields.
+func WithTitle(title string) Option {
+	return func(m *model) {
+		m.title = title
+	}
+}
+
+// WithUsernameChars restricts the username to the characters in charset.
+// Disallowed characters are dropped as they are typed, unless highlight is
+// set, in which case they are kept, highlighted and rejected on submit.
+func WithUsernameChars(charset string, highlight bool) Option {
+	return func(m *model) {
+		m.usernameChars = charset
+		m.highlightInvalid = highlight
+	}
+}
+
+// WithUsernameTaken checks usernames against taken once typing pauses, for
+// registration flows. A taken username blocks submit, and the first free
+// numbered variant is shown as ghost text that Ctrl+G accepts. taken runs
+// off the UI goroutine.
+func WithUsernameTaken(taken func(string) bool) Option {
+	return func(m *model) {
+		m.usernameTaken = taken
+		m.usernameInput.ShowSuggestions = true
+		m.usernameInput.KeyMap.AcceptSuggestion = acceptSuggestionKey
+	}
+}
+
+// WithUsernameTransform applies t to the username on submit, previewing
+// the result beneath the field.
+func WithUsernameTransform(t func(string) string) Option {
+	return func(m *model) {
+		m.usernameTransform = t
+	}
+}
+
+// WithUsername prefills the username, e.g. to resume a partly filled
+// form. The focus starts on the first empty field.
+func WithUsername(username string) Option {
+	return func(m *model) {
+		m.usernameInput.SetValue(username)
+	}
+}
+
+// WithPassword prefills the password. The focus starts on the first empty
+// field.
+func WithPassword(password string) Option {
+	return func(m *model) {
+		m.passwordInput.SetValue(password)
+	}
+}
+
+// WithNetrc prefills the username, and the password too when
+// withPassword is set, from the .netrc entry for host; the form then
+// starts on the first field left empty. Nothing is submitted until the user
+// confirms. A missing .netrc or host leaves the form blank.
+func WithNetrc(host string, withPassword bool) Option {
+	return func(m *model) {
+		entry, ok := lookupNetrc(host)
+		if !ok {
+			return
+		}
+		m.usernameInput.SetValue(entry.login)
+		if withPassword {
+			m.passwordInput.SetValue(entry.password)
+		}
+	}
+}
+
+// WithUsernameLength bounds the username length. A zero max means no
+// upper bound.
+func WithUsernameLength(minLen, maxLen int) Option {
+	return func(m *model) {
+		m.usernameLength = lengthPolicy{min: minLen, max: maxLen}
+	}
+}
+
+// WithStrengthMeter shows the password's strength, Weak, Medium or Strong,
+// beneath it as it is typed.
+func WithStrengthMeter() Option {
+	return func(m *model) {
+		m.showStrength = true
+	}
+}
+
+// WithTOTP adds a box after the password for a 6-digit one-time code, as
+// required by two-factor logins. Only digits can be typed into it, and it
+// is returned as Credentials.TOTP.
+func WithTOTP() Option {
+	return func(m *model) {
+		m.totp = true
+	}
+}
+
+// WithEmail asks for an email address instead of a username. It is
+// validated for a plausible format on submit and returned as the
+// username.
+func WithEmail() Option {
+	return func(m *model) {
+		m.email = true
+		m.usernameInput.Placeholder = "Enter email"
+	}
+}
+
+// WithUsernamePattern requires the username to match re on submit, e.g.
+// regexp.MustCompile(`^[a-z0-9_]{3,20}$`). Anchor the pattern to match the
+// whole username. A nil re disables the check.
+func WithUsernamePattern(re *regexp.Regexp) Option {
+	return func(m *model) {
+		m.usernamePattern = re
+	}
+}
+
+// WithCharLimits stops the username and password from growing beyond the
+// given number of characters, showing a counter beneath each limited
+// field. Zero leaves a field unlimited.
+func WithCharLimits(username, password int) Option {
+	return func(m *model) {
+		m.usernameInput.CharLimit = username
+		m.passwordInput.CharLimit = password
+		m.confirmInput.CharLimit = password
+	}
+}
+
+// WithPasswordLength bounds the password length. A zero max means no
+// upper bound.
+func WithPasswordLength(minLen, maxLen int) Option {
+	return func(m *model) {
+		m.passwordLength = lengthPolicy{min: minLen, max: maxLen}
+	}
+}
+
+// WithRegister adds a confirm password field for registration flows. The
+// confirmation must match the password, and the mismatch error updates live
+// as either field is edited.
+func WithRegister() Option {
+	return func(m *model) {
+		m.register = true
+	}
+}
+
+// WithConfirmValidator checks the confirm field with validate in addition
+// to the match check. It has no effect outside register mode.
+func WithConfirmValidator(validate func(string) error) Option {
+	return func(m *model) {
+		m.confirmValidate = validate
+	}
+}
+
+// WithEnterAdvances makes Enter move to the next field like Tab, so the
+// form is only submitted with Ctrl+S or Ctrl+D.
+func WithEnterAdvances() Option {
+	return func(m *model) {
+		m.enterAdvances = true
+	}
+}
+
+// WithLiteralTab makes Tab insert a literal tab into the focused input,
+// for values that may contain one. Down and Up, or Shift+Tab, move between
+// the fields instead; terminals don't report Ctrl+Tab distinctly.
+func WithLiteralTab() Option {
+	return func(m *model) {
+		m.literalTab = true
+	}
+}
+
+// WithInitialFocus starts the form on the field at index i, in focus
+// order, instead of the first empty one. Out of range indexes are clamped
+// to the first or last field.
+func WithInitialFocus(i int) Option {
+	return func(m *model) {
+		m.initialFocus = i
+		m.initialFocusSet = true
+	}
+}
+
+// WithVimKeys lets j and k move the focus down and up while the focused
+// field is empty. Once it holds text they are typed as usual.
+func WithVimKeys() Option {
+	return func(m *model) {
+		m.vimKeys = true
+	}
+}
+
+// WithValidationBorders colors each field's border red while its value
+// fails validation and green once it passes, as it is typed. Empty fields
+// keep the theme's border until a submit is attempted.
+func WithValidationBorders() Option {
+	return func(m *model) {
+		m.validationBorders = true
+	}
+}
+
+// WithBell rings the terminal bell when a submit is blocked by
+// validation.
+func WithBell() Option {
+	return func(m *model) {
+		m.bell = true
+	}
+}
+
+// WithErrorFlash briefly lights the border of the first invalid field when
+// a submit is blocked by validation.
+func WithErrorFlash() Option {
+	return func(m *model) {
+		m.errorFlash = true
+	}
+}
+
+// WithValidateOnBlur validates each field as soon as it loses focus, not
+// only on submit.
+func WithValidateOnBlur() Option {
+	return func(m *model) {
+		m.validateOnBlur = true
+	}
+}
+
+// WithErrorPlacement selects where validation errors are rendered.
+func WithErrorPlacement(p ErrorPlacement) Option {
+	return func(m *model) {
+		m.errorPlacement = p
+	}
+}
+
+// WithGuest offers guest access on key k, e.g. "ctrl+o". Pressing it
+// completes the form with empty credentials, skipping validation, and
+// Run reports it with Credentials.Guest.
+func WithGuest(k string) Option {
+	return func(m *model) {
+		m.guestKey = newGuestKey(k)
+	}
+}
+
+// WithFocusChange calls fn whenever focus moves between fields, however it
+// was moved, with the names of the fields losing and gaining focus:
+// "username", "password", "confirm", or the label given to AddField.
+func WithFocusChange(fn func(from, to string)) Option {
+	return func(m *model) {
+		m.onFocusChange = fn
+	}
+}
+
+// WithPersistentReveal remembers the Ctrl+R password reveal across
+// sessions, under the user's config directory. Without it the password
+// always starts masked and nothing is saved.
+func WithPersistentReveal() Option {
+	return func(m *model) {
+		m.persistReveal = true
+	}
+}
+
+// WithRememberMe adds a "Remember me" checkbox below the password,
+// toggled with Space. When it is ticked on submit, the username is stored
+// under the user's config directory and prefilled, with the box ticked,
+// on the next launch; submitting with it unticked forgets it.
+func WithRememberMe() Option {
+	return func(m *model) {
+		m.offerRemember = true
+	}
+}
+
+// WithForgotPassword adds a "Forgot password?" action below the fields.
+// Activating it with Enter, Space or a click calls fn in the background,
+// e.g. OpenURL to show a reset page, and leaves the form open.
+func WithForgotPassword(fn func()) Option {
+	return func(m *model) {
+		m.forgotPassword = fn
+	}
+}
+
+// WithConfirmEmpty asks for confirmation before submitting with blank
+// fields.
+func WithConfirmEmpty() Option {
+	return func(m *model) {
+		m.confirmEmpty = true
+	}
+}
+
+// WithSubmit calls submit with the username and password on submit, in
+// the background while a spinner is shown. If it returns an error, the
+// form stays open and shows the error; otherwise it completes as usual.
+func WithSubmit(submit func(username, password string) error) Option {
+	return func(m *model) {
+		m.onSubmit = submit
+	}
+}
+
+// WithRetryLimit locks the form for cooldown after maxAttempts submits in
+// a row are rejected by the submit callback, showing a countdown. A
+// successful submit resets the count.
+func WithRetryLimit(maxAttempts int, cooldown time.Duration) Option {
+	return func(m *model) {
+		m.maxAttempts = maxAttempts
+		m.lockout = cooldown
+	}
+}
+
+// WithReview shows a summary of the entered values, password masked, on
+// submit. Only y completes the form; n returns to it for editing.
+func WithReview() Option {
+	return func(m *model) {
+		m.review = true
+	}
+}
+
+// WithPasswordSummary selects how the result screen describes the
+// password: as hidden, the default, by length, by strength, or not at all.
+func WithPasswordSummary(s PasswordSummary) Option {
+	return func(m *model) {
+		m.passwordSummary = s
+	}
+}
+
+// WithJSONOutput skips the result screen and, once the program has exited,
+// prints the credentials to the output as
+// {"username":"...","password":"..."}.
+func WithJSONOutput() Option {
+	return func(m *model) {
+		m.resultMode = resultJSON
+	}
+}
+
+// WithCredentialsOutput skips the result screen and, once the program has
+// exited, writes the username and password to w on separate lines, for
+// credential helpers reading from a pipe or file descriptor.
+func WithCredentialsOutput(w io.Writer) Option {
+	return func(m *model) {
+		m.resultMode = resultLines
+		m.run.credentialsOut = w
+	}
+}
+
+// WithQuitOnSubmit ends the program as soon as the form is submitted,
+// skipping the result screen.
+func WithQuitOnSubmit() Option {
+	return func(m *model) {
+		m.quitOnSubmit = true
+	}
+}
+
+// WithSubmitAnimation briefly flashes the borders green on a successful
+// submit before completing.
+func WithSubmitAnimation() Option {
+	return func(m *model) {
+		m.animateSubmit = true
+	}
+}
+
+// WithDismissOnKey closes the result screen on any key rather than only
+// Ctrl+C.
+func WithDismissOnKey() Option {
+	return func(m *model) {
+		m.dismissOnKey = true
+	}
+}
+
+// WithTheme draws the form with t. Text colors set with WithTextColors
+// after it take precedence.
+func WithTheme(t Theme) Option {
+	return func(m *model) {
+		m.theme = t
+		m.focusedColor, m.blurredColor = "", ""
+	}
+}
+
+// WithTextColors overrides the input text color when focused and blurred.
+// An empty color keeps the default.
+func WithTextColors(focused, blurred string) Option {
+	return func(m *model) {
+		if focused != "" {
+			m.focusedColor = focused
+		}
+		if blurred != "" {
+			m.blurredColor = blurred
+		}
+	}
+}
+
+// WithForceMask starts with the password masked regardless of the saved
+// preference.
+func WithForceMask() Option {
+	return func(m *model) {
+		m.forceMask = true
+		*m = m.setShowPassword(false)
+	}
+}
+
+// WithEchoCharacter masks secret values with r instead of the default
+// '*', e.g. '•' on terminals that render it.
+func WithEchoCharacter(r rune) Option {
+	return func(m *model) {
+		m.echoCharacter = r
+		m.passwordInput.EchoCharacter = r
+		m.confirmInput.EchoCharacter = r
+		for i := range m.extras {
+			m.extras[i].input.EchoCharacter = r
+		}
+	}
+}
+
+// WithRevealLast keeps the last n characters of the password visible
+// while the rest is masked. A value shorter than n is shown in full.
+func WithRevealLast(n int) Option {
+	return func(m *model) {
+		m.revealLastN = n
+	}
+}
+
+// WithLastCharReveal shows each character typed into a masked field for
+// d before masking it, as on phone keyboards. Zero d uses half a second.
+func WithLastCharReveal(d time.Duration) Option {
+	return func(m *model) {
+		if d <= 0 {
+			d = defaultLastCharReveal
+		}
+		m.lastCharReveal = d
+	}
+}
+
+// WithPasswordGenerator lets Ctrl+G, pressed on a secret field, fill the
+// password with a random one of length characters, including at least one
+// from each of charsets, and show it for a few seconds so it can be noted.
+// Without charsets, CharsLower, CharsUpper, CharsDigits and CharsSymbols
+// are used. A length below one or an empty set is logged and the option
+// ignored. The password never exceeds the character limit set with
+// WithCharLimits, even if that leaves some sets out.
+func WithPasswordGenerator(length int, charsets ...string) Option {
+	return func(m *model) {
+		if length <= 0 {
+			log.Printf("password generator: length %d is not positive", length)
+			return
+		}
+		if len(charsets) == 0 {
+			charsets = []string{CharsLower, CharsUpper, CharsDigits, CharsSymbols}
+		}
+		for _, set := range charsets {
+			if set == "" {
+				log.Printf("password generator: empty character set")
+				return
+			}
+		}
+		m.generator = &passwordGenerator{length: length, charsets: charsets, rand: rand.Reader}
+	}
+}
+
+// WithMaskAfter masks a revealed password again after d of inactivity.
+func WithMaskAfter(d time.Duration) Option {
+	return func(m *model) {
+		m.maskAfter = d
+	}
+}
+
+// WithIdleTimeout closes the form after d without a key press or mouse
+// event, making Run return ErrTimeout. Zero disables it.
+func WithIdleTimeout(d time.Duration) Option {
+	return func(m *model) {
+		m.idleTimeout = d
+	}
+}
+
+// WithMaskOnTerminalBlur masks the password and dims the form while the
+// terminal window is unfocused.
+func WithMaskOnTerminalBlur() Option {
+	return func(m *model) {
+		m.maskOnTerminalBlur = true
+	}
+}
+
+// WithAltScreen runs the form on the terminal's alternate screen instead
+// of clearing it, so the previous content is restored on exit. The result
+// screen is printed again afterwards. WithInline takes precedence.
+func WithAltScreen() Option {
+	return func(m *model) {
+		m.run.altScreen = true
+	}
+}
+
+// WithInline renders the form in place below the existing terminal output,
+// without clearing the screen, so scrollback is preserved. The mouse is
+// disabled since the form's screen position is unknown.
+func WithInline() Option {
+	return func(m *model) {
+		m.inline = true
+		m.mouseEnabled = false
+	}
+}
+
+// WithMouse enables or disables mouse handling at startup. It can still be
+// toggled with Ctrl+T.
+func WithMouse(enabled bool) Option {
+	return func(m *model) {
+		m.mouseEnabled = enabled
+	}
+}
+
+// WithHelp shows or hides the key help beneath the form. It is shown by
+// default, and "?" expands it.
+func WithHelp(enabled bool) Option {
+	return func(m *model) {
+		m.showHelp = enabled
+	}
+}
+
+// WithStatusBar shows a status line with the validity of each field and
+// the name of the one being edited.
+func WithStatusBar() Option {
+	return func(m *model) {
+		m.showStatusBar = true
+	}
+}
+
+// WithPrewarmedSize probes the terminal size up front instead of waiting
+// for the first resize event.
+func WithPrewarmedSize() Option {
+	return func(m *model) {
+		m.width, m.height = terminalSizeOrDefault(m.run.debug)
+	}
+}
+
+// WithLogger writes a line to w for every key, mouse and resize event the
+// form handles, with the focused field and whether the form is done
+// afterwards, e.g. "event=key key=\"tab\" focus=password done=false".
+// Text typed into secret fields is logged as "<secret>". Lines meant for
+// stdout go to stderr, so they don't corrupt the form.
+func WithLogger(w io.Writer) Option {
+	return func(m *model) {
+		m.run.eventLog = eventLogWriter(w)
+	}
+}
+
+// WithMetrics logs the time taken to log in once the form is submitted.
+// If fromFirstKey is set it is measured from the first keystroke, so idle
+// time before typing is excluded.
+func WithMetrics(fromFirstKey bool) Option {
+	return func(m *model) {
+		m.run.metrics = true
+		m.timeFromFirstKey = fromFirstKey
+	}
+}
+
+// WithControl drives the form from newline-delimited JSON commands read
+// from the input instead of the keyboard.
+func WithControl() Option {
+	return func(m *model) {
+		m.run.control = true
+	}
+}
+
+// WithNonInteractive skips the form: the username and password are read
+// as the first two lines of the input, and the one-time code as the third
+// with WithTOTP, then validated and reported as if the form had been
+// submitted.
+func WithNonInteractive() Option {
+	return func(m *model) {
+		m.run.nonInteractive = true
+	}
+}
+
+// WithColorMode selects whether the form is drawn in color. The default,
+// ColorAuto, follows the terminal and NO_COLOR.
+func WithColorMode(mode ColorMode) Option {
+	return func(m *model) {
+		m.run.colorMode = mode
+	}
+}
+
+// WithInput reads keyboard input, or control commands, from r instead of
+// stdin.
+func WithInput(r io.Reader) Option {
+	return func(m *model) {
+		m.run.input = r
+	}
+}
+
+// WithOutput renders the form to w instead of stdout.
+func WithOutput(w io.Writer) Option {
+	return func(m *model) {
+		m.run.output = w
+	}
+}
+
+// WithDebug logs diagnostic decisions, such as whether mouse support was
+// enabled, to the standard logger.
+func WithDebug() Option {
+	return func(m *model) {
+		m.run.debug = true
+	}
+}
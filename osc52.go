@@ -0,0 +1,62 @@
+package cornice
+
+import (
+	"encoding/base64"
+	"io"
+	"log"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
+)
+
+// copyKey copies the username to the terminal's clipboard.
+const copyKey = "ctrl+y"
+
+// osc52 returns the escape sequence asking the terminal to set its
+// clipboard to s. Inside tmux it is wrapped so tmux passes it through.
+func osc52(s string) string {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(s)) + "\x07"
+	if os.Getenv("TMUX") != "" {
+		seq = "\x1bPtmux;\x1b" + seq + "\x1b\\"
+	}
+	return seq
+}
+
+// terminalOutput returns where the program renders.
+func (m model) terminalOutput() io.Writer {
+	if m.run.output != nil {
+		return m.run.output
+	}
+	return os.Stdout
+}
+
+// supportsOSC52 reports whether the output is a terminal that may honour
+// OSC 52. Unlike the system clipboard this works over SSH, as the
+// sequence travels to the local terminal.
+func (m model) supportsOSC52() bool {
+	f, ok := m.terminalOutput().(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return false
+	}
+	switch os.Getenv("TERM") {
+	case "", "dumb", "linux":
+		return false
+	}
+	return true
+}
+
+// copyUsername copies the username to the terminal's clipboard with
+// OSC 52, or does nothing when the terminal can't take it.
+func (m model) copyUsername() tea.Cmd {
+	if !m.supportsOSC52() {
+		return nil
+	}
+	out, seq := m.terminalOutput(), osc52(m.typedUsername())
+	return func() tea.Msg {
+		if _, err := io.WriteString(out, seq); err != nil {
+			log.Printf("copy: %v", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,32 @@
+package cornice
+
+import (
+	"bytes"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestOSC52(t *testing.T) {
+	t.Setenv("TMUX", "")
+	// base64("alice") = "YWxpY2U="
+	if got, want := osc52("alice"), "\x1b]52;c;YWxpY2U=\x07"; got != want {
+		t.Errorf("osc52(alice) = %q, want %q", got, want)
+	}
+
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+	if got, want := osc52("alice"), "\x1bPtmux;\x1b\x1b]52;c;YWxpY2U=\x07\x1b\\"; got != want {
+		t.Errorf("osc52(alice) in tmux = %q, want %q", got, want)
+	}
+}
+
+func TestCopyUsernameNeedsTerminal(t *testing.T) {
+	var out bytes.Buffer
+	m := drive(initialModel(WithOutput(&out)), keys("alice")...)
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlY}); cmd != nil {
+		runCmd(cmd)
+	}
+	if out.Len() != 0 {
+		t.Errorf("wrote %q to an output that isn't a terminal", out.String())
+	}
+}
@@ -0,0 +1,48 @@
+//go:build pam
+
+package cornice
+
+import "github.com/msteinert/pam"
+
+// PAMAuthenticator returns a submit callback, for WithSubmit, that checks
+// the credentials against the PAM service, e.g. "login", and that the
+// account may be used. It is only built with the pam build tag.
+func PAMAuthenticator(service string) func(username, password string) error {
+	return pamAuthenticator(service, startPAM)
+}
+
+// startPAM starts a libpam transaction, translating its message styles
+// for respond.
+func startPAM(service, username string, respond func(pamStyle, string) (string, error)) (pamTransaction, error) {
+	tx, err := pam.StartFunc(service, username, func(s pam.Style, msg string) (string, error) {
+		style := pamOther
+		switch s {
+		case pam.PromptEchoOff:
+			style = pamPromptEchoOff
+		case pam.PromptEchoOn:
+			style = pamPromptEchoOn
+		case pam.ErrorMsg:
+			style = pamErrorMsg
+		case pam.TextInfo:
+			style = pamTextInfo
+		}
+		return respond(style, msg)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return libpamTransaction{tx}, nil
+}
+
+// libpamTransaction refuses empty passwords in both steps.
+type libpamTransaction struct {
+	tx *pam.Transaction
+}
+
+func (t libpamTransaction) Authenticate() error {
+	return t.tx.Authenticate(pam.DisallowNullAuthtok)
+}
+
+func (t libpamTransaction) AcctMgmt() error {
+	return t.tx.AcctMgmt(pam.DisallowNullAuthtok)
+}
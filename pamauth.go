@@ -0,0 +1,85 @@
+package cornice
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// errAuthFailed is shown when PAM rejects the credentials. The reasons
+// PAM's calls return are logged instead, as they may tell an attacker too
+// much; messages its modules send the user are shown.
+var errAuthFailed = errors.New("authentication failed")
+
+// pamStyle is the kind of message PAM sends the conversation handler.
+type pamStyle int
+
+const (
+	pamPromptEchoOff pamStyle = iota
+	pamPromptEchoOn
+	pamErrorMsg
+	pamTextInfo
+	pamOther
+)
+
+// pamTransaction is the part of a PAM transaction the authenticator uses.
+type pamTransaction interface {
+	Authenticate() error
+	AcctMgmt() error
+}
+
+// pamStarter starts a PAM transaction for username with the service,
+// answering PAM's messages with respond.
+type pamStarter func(service, username string, respond func(pamStyle, string) (string, error)) (pamTransaction, error)
+
+// pamConversation answers PAM's prompts with the entered credentials:
+// hidden prompts get the password and visible ones the username. The
+// messages meant for the user are collected in msgs rather than written
+// out, which would break up the form.
+func pamConversation(username, password string, msgs *[]string) func(pamStyle, string) (string, error) {
+	return func(s pamStyle, msg string) (string, error) {
+		switch s {
+		case pamPromptEchoOff:
+			return password, nil
+		case pamPromptEchoOn:
+			return username, nil
+		case pamErrorMsg, pamTextInfo:
+			if msg = strings.TrimSpace(msg); msg != "" {
+				*msgs = append(*msgs, msg)
+			}
+			return "", nil
+		}
+		return "", errors.New("unrecognized message style")
+	}
+}
+
+// pamAuthenticator returns a submit callback that checks the credentials
+// with transactions from start, and that the account may be used. A
+// failure carries the messages PAM sent the user, so the form shows them
+// beneath the fields.
+func pamAuthenticator(service string, start pamStarter) func(username, password string) error {
+	return func(username, password string) error {
+		var msgs []string
+		failed := func() error {
+			if len(msgs) == 0 {
+				return errAuthFailed
+			}
+			return fmt.Errorf("%w: %s", errAuthFailed, strings.Join(msgs, " "))
+		}
+		tx, err := start(service, username, pamConversation(username, password, &msgs))
+		if err != nil {
+			log.Printf("pam: start %s: %v", service, err)
+			return failed()
+		}
+		if err := tx.Authenticate(); err != nil {
+			log.Printf("pam: authenticate: %v", err)
+			return failed()
+		}
+		if err := tx.AcctMgmt(); err != nil {
+			log.Printf("pam: account: %v", err)
+			return failed()
+		}
+		return nil
+	}
+}
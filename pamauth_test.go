@@ -0,0 +1,100 @@
+package cornice
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakePAM is a PAM transaction that prompts like pam_unix and accepts
+// only the password it holds.
+type fakePAM struct {
+	password  string
+	respond   func(pamStyle, string) (string, error)
+	expired   bool
+	startErr  error
+	gotUser   string
+	gotSecret string
+}
+
+func (f *fakePAM) start(service, username string, respond func(pamStyle, string) (string, error)) (pamTransaction, error) {
+	if f.startErr != nil {
+		return nil, f.startErr
+	}
+	f.respond = respond
+	return f, nil
+}
+
+func (f *fakePAM) Authenticate() error {
+	var err error
+	if f.gotUser, err = f.respond(pamPromptEchoOn, "login:"); err != nil {
+		return err
+	}
+	if f.gotSecret, err = f.respond(pamPromptEchoOff, "Password:"); err != nil {
+		return err
+	}
+	if f.gotSecret != f.password {
+		return errors.New("Authentication failure")
+	}
+	return nil
+}
+
+func (f *fakePAM) AcctMgmt() error {
+	if f.expired {
+		if _, err := f.respond(pamErrorMsg, "Your account has expired; please contact your system administrator"); err != nil {
+			return err
+		}
+		return errors.New("User account has expired")
+	}
+	return nil
+}
+
+func TestPAMAuthenticatorSuccess(t *testing.T) {
+	f := &fakePAM{password: "secret"}
+	if err := pamAuthenticator("login", f.start)("alice", "secret"); err != nil {
+		t.Fatalf("valid credentials: %v", err)
+	}
+	if f.gotUser != "alice" || f.gotSecret != "secret" {
+		t.Errorf("PAM was answered %q/%q, want alice/secret", f.gotUser, f.gotSecret)
+	}
+}
+
+func TestPAMAuthenticatorFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		pam  *fakePAM
+		want string
+	}{
+		{"wrong password", &fakePAM{password: "other"}, "authentication failed"},
+		{"expired account", &fakePAM{password: "secret", expired: true},
+			"authentication failed: Your account has expired; please contact your system administrator"},
+		{"unknown service", &fakePAM{startErr: errors.New("no such service")}, "authentication failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := pamAuthenticator("login", tt.pam.start)("alice", "secret")
+			if !errors.Is(err, errAuthFailed) || err.Error() != tt.want {
+				t.Errorf("err = %v, want %q with only the messages meant for the user", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPAMConversationUnknownStyle(t *testing.T) {
+	var msgs []string
+	if _, err := pamConversation("alice", "secret", &msgs)(pamOther, ""); err == nil {
+		t.Error("an unknown message style was answered")
+	}
+}
+
+func TestPAMFailureShownInForm(t *testing.T) {
+	f := &fakePAM{password: "secret", expired: true}
+	m := initialModel(WithSubmit(pamAuthenticator("login", f.start)))
+	m = drive(m, tea.WindowSizeMsg{Width: 80, Height: 24})
+	m = drive(m, script(keys("alice"), []tea.Msg{tea.KeyMsg{Type: tea.KeyTab}}, keys("secret"), []tea.Msg{tea.KeyMsg{Type: tea.KeyEnter}})...)
+	if m.done || !strings.Contains(m.View(), "Your account has expired") {
+		t.Errorf("done = %v; want PAM's message shown beneath the form:\n%s", m.done, m.View())
+	}
+}
@@ -0,0 +1,77 @@
+package cornice
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrorPlacement selects where validation errors are rendered.
+type ErrorPlacement int
+
+const (
+	// ErrorsBelow renders each error inside its box, below the input.
+	ErrorsBelow ErrorPlacement = iota
+	// ErrorsAbove renders each error inside its box, above the input.
+	ErrorsAbove
+	// ErrorsFooter collects all errors in a footer beneath the form.
+	ErrorsFooter
+)
+
+// ParseErrorPlacement parses "below", "above" or "footer".
+func ParseErrorPlacement(s string) (ErrorPlacement, error) {
+	switch s {
+	case "below":
+		return ErrorsBelow, nil
+	case "above":
+		return ErrorsAbove, nil
+	case "footer":
+		return ErrorsFooter, nil
+	}
+	return ErrorsBelow, fmt.Errorf("unknown error placement %q", s)
+}
+
+// fieldContent lays out a box's content: title, input and, depending on
+// the placement, its error.
+func (m model) fieldContent(title, input, err string) string {
+	lines := []string{m.theme.Title.Render(title)}
+	switch {
+	case err == "" || m.errorPlacement == ErrorsFooter:
+		lines = append(lines, input)
+	case m.errorPlacement == ErrorsAbove:
+		lines = append(lines, m.style(errorStyle).Render(err), input)
+	default:
+		lines = append(lines, input, m.style(errorStyle).Render(err))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fieldErrors returns the current errors in field order.
+func (m model) fieldErrors() []string {
+	var errs []string
+	for _, f := range m.fields() {
+		if err := m.err(f); err != "" {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// footer renders the consolidated error footer, if errors go there.
+func (m model) footer() string {
+	if m.errorPlacement != ErrorsFooter {
+		return ""
+	}
+	errs := m.fieldErrors()
+	for i, err := range errs {
+		errs[i] = m.style(errorStyle).Render(err)
+	}
+	return strings.Join(errs, "\n")
+}
+
+// footerHeight is the number of rows the footer takes up.
+func (m model) footerHeight() int {
+	if m.errorPlacement != ErrorsFooter {
+		return 0
+	}
+	return len(m.fieldErrors())
+}
@@ -0,0 +1,51 @@
+package cornice
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseErrorPlacement(t *testing.T) {
+	for s, want := range map[string]ErrorPlacement{"below": ErrorsBelow, "above": ErrorsAbove, "footer": ErrorsFooter} {
+		if got, err := ParseErrorPlacement(s); err != nil || got != want {
+			t.Errorf("ParseErrorPlacement(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseErrorPlacement("left"); err == nil {
+		t.Error(`ParseErrorPlacement("left") succeeded`)
+	}
+}
+
+func TestErrorPlacementInView(t *testing.T) {
+	tests := []struct {
+		placement ErrorPlacement
+		// where reports whether the error at errAt is placed right,
+		// relative to the username input at inputAt and the password
+		// title at passwordAt, all byte offsets into the view.
+		where func(errAt, inputAt, passwordAt int) bool
+	}{
+		{ErrorsBelow, func(errAt, inputAt, passwordAt int) bool { return inputAt < errAt && errAt < passwordAt }},
+		{ErrorsAbove, func(errAt, inputAt, passwordAt int) bool { return errAt < inputAt }},
+		{ErrorsFooter, func(errAt, inputAt, passwordAt int) bool { return passwordAt < errAt }},
+	}
+	for _, tt := range tests {
+		m := initialModel(WithErrorPlacement(tt.placement), WithUsernameLength(3, 0), WithColorMode(ColorNever))
+		m = drive(m, script([]tea.Msg{tea.WindowSizeMsg{Width: 80, Height: 40}}, keys("al"), []tea.Msg{tea.KeyMsg{Type: tea.KeyEnter}})...)
+		errText := m.err(fieldUsername)
+		if errText == "" {
+			t.Fatal("submitting a short username left no error")
+		}
+		view := m.View()
+		errAt := strings.Index(view, errText)
+		inputAt := strings.Index(view, "> al")
+		passwordAt := strings.Index(view, "Password")
+		if errAt < 0 || inputAt < 0 || passwordAt < 0 {
+			t.Fatalf("placement %d: view lacks the error, input or password title:\n%s", tt.placement, view)
+		}
+		if !tt.where(errAt, inputAt, passwordAt) {
+			t.Errorf("placement %d: error misplaced:\n%s", tt.placement, view)
+		}
+	}
+}
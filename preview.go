@@ -0,0 +1,32 @@
+package cornice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Preview renders the form's first frame for a width×height terminal
+// without starting the program, e.g. for screenshots or golden files.
+func Preview(width, height int, opts ...Option) string {
+	m := initialModel(opts...)
+	// Set the size directly rather than through resize, so the made-up
+	// dimensions aren't remembered as the terminal's.
+	m.width, m.height = width, height
+	return m.scrollToFocus().View()
+}
+
+// ParseSize parses a terminal size given as "WxH", e.g. "80x24".
+func ParseSize(s string) (width, height int, err error) {
+	w, h, ok := strings.Cut(strings.ToLower(s), "x")
+	if ok {
+		width, err = strconv.Atoi(w)
+	}
+	if ok && err == nil {
+		height, err = strconv.Atoi(h)
+	}
+	if !ok || err != nil || width <= 0 || height <= 0 {
+		return 0, 0, fmt.Errorf("invalid size %q, want WxH", s)
+	}
+	return width, height, nil
+}
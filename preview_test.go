@@ -0,0 +1,67 @@
+package cornice
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// golden compares got with testdata/name, rewriting it with -update.
+func golden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("%s differs from the golden file:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+func TestPreview80x24(t *testing.T) {
+	got := Preview(80, 24, WithColorMode(ColorNever))
+	for _, title := range []string{"Username", "Password"} {
+		if !strings.Contains(got, title) {
+			t.Errorf("preview lacks the %s title", title)
+		}
+	}
+	if w, h := lipgloss.Width(got), lipgloss.Height(got); w != 80 || h != 24 {
+		t.Errorf("preview is %dx%d, want 80x24", w, h)
+	}
+	golden(t, "preview_80x24.golden", got)
+	if again := Preview(80, 24, WithColorMode(ColorNever)); again != got {
+		t.Error("preview differs between runs")
+	}
+}
+
+func TestPreviewDoesNotRememberSize(t *testing.T) {
+	rememberSize(0, 0)
+	Preview(33, 11, WithColorMode(ColorNever))
+	if sizeCache.width != 0 || sizeCache.height != 0 {
+		t.Errorf("preview size %dx%d was remembered as the terminal's", sizeCache.width, sizeCache.height)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	w, h, err := ParseSize("80x24")
+	if err != nil || w != 80 || h != 24 {
+		t.Errorf("ParseSize(80x24) = %d, %d, %v", w, h, err)
+	}
+	for _, bad := range []string{"", "80", "80x", "x24", "0x24", "80x-1", "axb"} {
+		if _, _, err := ParseSize(bad); err == nil {
+			t.Errorf("ParseSize(%q) accepted", bad)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package cornice
+
+import (
+	"log"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateRemember handles keys while the remember checkbox is focused:
+// Space toggles it and other input is ignored.
+func (m model) updateRemember(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.Type == tea.KeySpace {
+		m.remember = !m.remember
+	}
+	return m, nil
+}
+
+// rememberView renders the remember checkbox.
+func (m model) rememberView(focused bool) string {
+	box := "[ ]"
+	if m.remember {
+		box = "[x]"
+	}
+	style := m.blurredText
+	if focused {
+		style = m.theme.Title
+	}
+	return style.Render(box + " " + m.fieldTitle(fieldRemember))
+}
+
+// saveRemembered stores the username for the next launch if the checkbox
+// is ticked, and forgets any stored one otherwise.
+func (m model) saveRemembered() {
+	username := ""
+	if m.remember {
+		username = m.typedUsername()
+	}
+	err := updateState(func(s *state) {
+		s.Username = username
+	})
+	if err != nil {
+		log.Printf("state: %v", err)
+	}
+}
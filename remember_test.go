@@ -0,0 +1,65 @@
+package cornice
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var space = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+
+func TestRememberCheckboxToggles(t *testing.T) {
+	m := initialModel(WithRememberMe())
+	fs := m.fields()
+	if fs[len(fs)-1] != fieldRemember {
+		t.Fatalf("fields = %v, want the checkbox last in the focus cycle", fs)
+	}
+	m = drive(m, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyTab})
+	if m.focusedField() != fieldRemember {
+		t.Fatalf("focused %v, want the checkbox after the password", m.focusedField())
+	}
+	if m.rememberView(true) != m.theme.Title.Render("[ ] Remember me") {
+		t.Errorf("unchecked box renders %q", m.rememberView(true))
+	}
+
+	m = drive(m, space)
+	if !m.remember {
+		t.Fatal("Space didn't tick the checkbox")
+	}
+	if m.rememberView(true) != m.theme.Title.Render("[x] Remember me") {
+		t.Errorf("checked box renders %q", m.rememberView(true))
+	}
+	m = drive(m, keys("a")...)
+	if !m.remember || m.usernameInput.Value() != "" || m.passwordInput.Value() != "" {
+		t.Error("typing on the checkbox changed it or a field")
+	}
+	if m = drive(m, space); m.remember {
+		t.Error("a second Space didn't clear the checkbox")
+	}
+}
+
+func TestRememberRoundTrip(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := initialModel(WithRememberMe())
+	m.usernameInput.SetValue("alice")
+	m.remember = true
+	m.saveRemembered()
+
+	next := initialModel(WithRememberMe())
+	if v := next.usernameInput.Value(); v != "alice" || !next.remember {
+		t.Fatalf("username = %q, remember = %v on the next launch; want alice, ticked", v, next.remember)
+	}
+	if next.focusedField() != fieldPassword {
+		t.Errorf("focused %v, want the password after a remembered username", next.focusedField())
+	}
+	if v := initialModel().usernameInput.Value(); v != "" {
+		t.Errorf("username = %q without WithRememberMe, want none", v)
+	}
+
+	// Unticking forgets it.
+	next.remember = false
+	next.saveRemembered()
+	if v := initialModel(WithRememberMe()).usernameInput.Value(); v != "" {
+		t.Errorf("username = %q after unticking, want it forgotten", v)
+	}
+}
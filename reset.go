@@ -0,0 +1,41 @@
+package cornice
+
+import "time"
+
+// reset clears the form for another attempt; see Form.Reset.
+func (m model) reset() model {
+	m.usernameInput.Reset()
+	m.passwordInput.Reset()
+	m.confirmInput.Reset()
+	m.totpInput.Reset()
+	m.extras = append([]extraField(nil), m.extras...)
+	for i := range m.extras {
+		m.extras[i].input.Reset()
+		m.extras[i].err = ""
+	}
+
+	m = m.hideGenerated()
+	m = m.setFocus(0)
+	m.errs = [numFields]string{}
+	m.attemptedSubmit = false
+	m.availability = availabilityMsg{}
+	m.submitErr = ""
+	m.submitting = false
+	m.confirming = nil
+	m.animating = false
+	m.errorFlashing = false
+	m.done = false
+	m.guest = false
+	m.timedOut = false
+	m.capsLock = false
+	m.pasted = false
+	m.lastCharShown = false
+	m.escapedAt = time.Time{}
+	m.usernameEditedAt = time.Time{}
+
+	m.startedAt = m.now()
+	m.lastActivity = m.startedAt
+	m.firstKeyAt = time.Time{}
+	m.submittedAt = time.Time{}
+	return m
+}
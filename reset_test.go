@@ -0,0 +1,59 @@
+package cornice
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestResetRestoresPristineForm(t *testing.T) {
+	opts := []Option{
+		WithRegister(),
+		WithTOTP(),
+		WithLastCharReveal(time.Second),
+		WithColorMode(ColorNever),
+		WithSubmit(func(username, password string) error {
+			return errors.New("rejected")
+		}),
+	}
+	size := tea.WindowSizeMsg{Width: 80, Height: 24}
+	fresh := drive(initialModel(opts...), size)
+
+	tab := []tea.Msg{tea.KeyMsg{Type: tea.KeyTab}}
+	m := drive(fresh, script(
+		keys("alice"), tab,
+		[]tea.Msg{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("SECRET"), Paste: true}},
+		tab, keys("other"),
+		[]tea.Msg{tea.KeyMsg{Type: tea.KeyEnter}},
+	)...)
+	if !m.attemptedSubmit || m.View() == fresh.View() {
+		t.Fatal("the script left nothing to reset")
+	}
+	// Flags the script can't reliably reach without a terminal.
+	m.capsLock = true
+	m.lastCharShown = true
+	m.escapedAt = time.Now()
+
+	m = drive(m, resetMsg{})
+	if m.capsLock || m.pasted || m.lastCharShown || !m.escapedAt.IsZero() {
+		t.Errorf("capsLock = %v, pasted = %v, lastCharShown = %v, escapedAt = %v; want all cleared",
+			m.capsLock, m.pasted, m.lastCharShown, m.escapedAt)
+	}
+	if !m.register || !m.totp || m.lastCharReveal != time.Second {
+		t.Error("reset dropped the configured options")
+	}
+	if got, want := m.View(), fresh.View(); got != want {
+		t.Errorf("reset form differs from a fresh one:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFormResetBeforeRun(t *testing.T) {
+	f := NewForm()
+	f.m.usernameInput.SetValue("alice")
+	f.Reset()
+	if v := f.m.usernameInput.Value(); v != "" {
+		t.Errorf("username = %q after Reset, want it cleared", v)
+	}
+}
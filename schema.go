@@ -0,0 +1,87 @@
+package cornice
+
+import "encoding/json"
+
+// fieldSchema describes one field of the form; see Schema.
+type fieldSchema struct {
+	Name        string   `json:"name"`
+	Label       string   `json:"label"`
+	Placeholder string   `json:"placeholder,omitempty"`
+	Secret      bool     `json:"secret"`
+	Required    bool     `json:"required"`
+	MinLength   int      `json:"min_length,omitempty"`
+	MaxLength   int      `json:"max_length,omitempty"`
+	Charset     string   `json:"charset,omitempty"`
+	Pattern     string   `json:"pattern,omitempty"`
+	Validators  []string `json:"validators,omitempty"`
+}
+
+// formSchema describes the form as a whole.
+type formSchema struct {
+	Fields []fieldSchema `json:"fields"`
+}
+
+// Schema describes the form configured by opts as JSON: its fields, in
+// order, with their labels, placeholders and validation rules. It carries
+// no values.
+func Schema(opts ...Option) ([]byte, error) {
+	return initialModel(opts...).Schema()
+}
+
+// Schema describes the model's fields as JSON.
+func (m model) Schema() ([]byte, error) {
+	var s formSchema
+	for _, f := range m.fields() {
+		if m.input(f) == nil {
+			continue
+		}
+		fs := fieldSchema{
+			Name:        m.fieldName(f),
+			Label:       m.fieldTitle(f),
+			Placeholder: m.input(f).Placeholder,
+			Secret:      m.fieldSecret(f),
+		}
+		switch f {
+		case fieldUsername:
+			fs.MinLength, fs.MaxLength = m.usernameLength.min, m.usernameLength.max
+			fs.Charset = m.usernameChars
+			if m.usernameChars != "" {
+				fs.Validators = append(fs.Validators, "charset")
+			}
+			if m.email {
+				fs.Validators = append(fs.Validators, "email")
+			}
+			if m.usernamePattern != nil {
+				fs.Pattern = m.usernamePattern.String()
+				fs.Validators = append(fs.Validators, "pattern")
+			}
+			if m.usernameTaken != nil {
+				fs.Validators = append(fs.Validators, "available")
+			}
+		case fieldPassword:
+			fs.MinLength, fs.MaxLength = m.passwordLength.min, m.passwordLength.max
+		case fieldTOTP:
+			fs.MinLength, fs.MaxLength = totpLength, totpLength
+			fs.Charset = totpDigits
+			fs.Validators = append(fs.Validators, "charset")
+		case fieldConfirm:
+			fs.Validators = append(fs.Validators, "match")
+			if m.confirmValidate != nil {
+				fs.Validators = append(fs.Validators, "custom")
+			}
+		default:
+			if m.extra(f).validate != nil {
+				fs.Validators = append(fs.Validators, "custom")
+			}
+		}
+		if limit := m.input(f).CharLimit; limit > 0 && (fs.MaxLength == 0 || limit < fs.MaxLength) {
+			fs.MaxLength = limit
+		}
+		if fs.MinLength > 0 || fs.MaxLength > 0 {
+			fs.Validators = append(fs.Validators, "length")
+		}
+		fs.Required = fs.MinLength > 0
+		s.Fields = append(s.Fields, fs)
+	}
+	return json.MarshalIndent(s, "", "  ")
+}
@@ -0,0 +1,71 @@
+package cornice
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestSchemaRoundTrip(t *testing.T) {
+	m := initialModel(
+		WithUsernameLength(3, 20),
+		WithUsernameChars(usernameCharset, false),
+		WithUsernamePattern(regexp.MustCompile(`^[a-z]`)),
+		WithPasswordLength(8, 0),
+		WithRegister(),
+		WithTOTP(),
+	)
+	m = m.addField("PIN", "4 digits", WithFieldSecret(), WithFieldValidator(func(string) error {
+		return errors.New("bad")
+	}))
+	data, err := m.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var raw struct {
+		Fields []map[string]any `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"name", "label", "placeholder", "secret", "required", "min_length", "max_length", "charset", "pattern", "validators"} {
+		if _, ok := raw.Fields[0][key]; !ok {
+			t.Errorf("username schema lacks %q:\n%s", key, data)
+		}
+	}
+
+	var got formSchema
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []fieldSchema{
+		{Name: "username", Label: "Username", Placeholder: "Enter username", Required: true, MinLength: 3, MaxLength: 20,
+			Charset: usernameCharset, Pattern: "^[a-z]", Validators: []string{"charset", "pattern", "length"}},
+		{Name: "password", Label: "Password", Placeholder: "Enter password", Secret: true, Required: true, MinLength: 8,
+			Validators: []string{"length"}},
+		{Name: "confirm", Label: "Confirm Password", Placeholder: "Re-enter password", Secret: true, Validators: []string{"match"}},
+		{Name: "totp", Label: "One-time code", Placeholder: m.totpInput.Placeholder, Required: true, MinLength: totpLength, MaxLength: totpLength,
+			Charset: totpDigits, Validators: []string{"charset", "length"}},
+		{Name: "PIN", Label: "PIN", Placeholder: "4 digits", Secret: true, Validators: []string{"custom"}},
+	}
+	if !reflect.DeepEqual(got.Fields, want) {
+		t.Errorf("schema fields =\n%+v\nwant\n%+v", got.Fields, want)
+	}
+}
+
+func TestSchemaSkipsNonInputs(t *testing.T) {
+	data, err := Schema(WithRememberMe())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got formSchema
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Fields) != 2 {
+		t.Errorf("schema has %d fields, want only the username and password:\n%s", len(got.Fields), data)
+	}
+}
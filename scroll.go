@@ -0,0 +1,138 @@
+package cornice
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	scrollUpIndicator   = "▲ more"
+	scrollDownIndicator = "▼ more"
+
+	// wheelScrollRows is how far one notch of the mouse wheel scrolls.
+	wheelScrollRows = 3
+)
+
+// fieldHeight is the nominal number of rows a bordered field occupies.
+// Boxes grow beyond it when their content needs more lines.
+func (m model) fieldHeight() int {
+	return m.boxHeight() + 2
+}
+
+// fieldTops returns the row each rendered box starts at, plus the total
+// height of the form as a final element.
+func fieldTops(boxes []string) []int {
+	tops := make([]int, len(boxes)+1)
+	for i, box := range boxes {
+		tops[i+1] = tops[i] + lipgloss.Height(box)
+	}
+	return tops
+}
+
+// fieldRows is the number of rows left for the fields once the scroll
+// indicators and everything around the fields are accounted for.
+func (m model) fieldRows() int {
+	return m.availableHeight() - 2 - m.headerHeight() - m.belowFieldsHeight() - m.statusHeight() - m.helpHeight()
+}
+
+// viewportHeight returns how many rows of fields are shown at once,
+// honouring maxFields when set.
+func (m model) viewportHeight() int {
+	h := m.fieldRows()
+	if m.availableHeight() <= 0 {
+		h = fieldTops(m.fieldBoxes())[m.fieldCount()]
+	}
+	if m.maxFields > 0 && m.maxFields*m.fieldHeight() < h {
+		h = m.maxFields * m.fieldHeight()
+	}
+	if h < 1 {
+		h = 1
+	}
+	return h
+}
+
+// scrolling reports whether the form is taller than the visible area.
+func (m model) scrolling() bool {
+	return fieldTops(m.fieldBoxes())[m.fieldCount()] > m.viewportHeight()
+}
+
+// scrollToFocus adjusts the viewport so the focused field is fully visible.
+func (m model) scrollToFocus() model {
+	boxes := m.fieldBoxes()
+	tops := fieldTops(boxes)
+	m.viewport.Width = m.boxWidth() + 2
+	m.viewport.Height = m.viewportHeight()
+	m.viewport.SetContent(strings.Join(boxes, "\n"))
+
+	top := tops[m.focused]
+	bottom := tops[m.focused+1]
+	switch {
+	case top < m.viewport.YOffset:
+		m.viewport.SetYOffset(top)
+	case bottom > m.viewport.YOffset+m.viewport.Height:
+		m.viewport.SetYOffset(bottom - m.viewport.Height)
+	}
+	return m
+}
+
+// scrollBy moves the viewport n rows down, or up when n is negative,
+// leaving the focus where it is. Typing in the focused field brings it
+// back into view.
+func (m model) scrollBy(n int) model {
+	m.viewport.SetContent(strings.Join(m.fieldBoxes(), "\n"))
+	if n < 0 {
+		m.viewport.LineUp(-n)
+	} else {
+		m.viewport.LineDown(n)
+	}
+	return m
+}
+
+// scrolledForm renders the fields, wrapped in the viewport with scroll
+// indicators when they don't all fit.
+func (m model) scrolledForm() string {
+	boxes := m.fieldBoxes()
+	if !m.scrolling() {
+		return lipgloss.JoinVertical(lipgloss.Left, boxes...)
+	}
+
+	vp := m.viewport
+	vp.SetContent(strings.Join(boxes, "\n"))
+
+	up, down := "", ""
+	if !vp.AtTop() {
+		up = scrollUpIndicator
+	}
+	if !vp.AtBottom() {
+		down = scrollDownIndicator
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, up, vp.View(), down)
+}
+
+// fieldAt maps a point in the form to the index of the field rendered
+// there. Points left or right of the boxes hit nothing.
+func (m model) fieldAt(x, y int) (int, bool) {
+	if m.compact() {
+		return m.compactFieldAt(x, y)
+	}
+	y -= m.headerHeight()
+	if x < 0 || x >= m.boxWidth()+2 {
+		return 0, false
+	}
+	if m.scrolling() {
+		// Skip the indicator row and translate through the scroll offset.
+		y--
+		if y < 0 || y >= m.viewport.Height {
+			return 0, false
+		}
+		y += m.viewport.YOffset
+	}
+	tops := fieldTops(m.fieldBoxes())
+	for i := 0; i < m.fieldCount(); i++ {
+		if y >= tops[i] && y < tops[i+1] {
+			return i, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,65 @@
+package cornice
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tallForm returns a form with five fields in a terminal too short to
+// show them all.
+func tallForm() model {
+	m := initialModel(WithEmail(), WithRegister(), WithTOTP(), WithHelp(false))
+	return drive(m, tea.WindowSizeMsg{Width: 80, Height: 16})
+}
+
+// inView reports whether the box of the field at index i is fully within
+// the viewport.
+func inView(m model, i int) bool {
+	tops := fieldTops(m.fieldBoxes())
+	return tops[i] >= m.viewport.YOffset && tops[i+1] <= m.viewport.YOffset+m.viewport.Height
+}
+
+func TestFocusScrollsIntoView(t *testing.T) {
+	m := tallForm()
+	if !m.scrolling() || m.compact() {
+		t.Fatalf("scrolling = %v, compact = %v; want a scrolled layout", m.scrolling(), m.compact())
+	}
+	last := m.fieldCount() - 1
+	if inView(m, last) {
+		t.Fatal("the last field is visible before scrolling")
+	}
+
+	m = drive(m, tea.KeyMsg{Type: tea.KeyShiftTab})
+	if m.focused != last || m.viewport.YOffset == 0 || !inView(m, last) {
+		t.Fatalf("focused %d, offset %d; want the last field scrolled into view", m.focused, m.viewport.YOffset)
+	}
+	if v := m.View(); !strings.Contains(v, scrollUpIndicator) || strings.Contains(v, scrollDownIndicator) {
+		t.Errorf("the indicators don't match the scroll position:\n%s", v)
+	}
+
+	m = drive(m, tea.KeyMsg{Type: tea.KeyTab})
+	if m.focused != 0 || m.viewport.YOffset != 0 {
+		t.Errorf("focused %d, offset %d after wrapping; want back at the top", m.focused, m.viewport.YOffset)
+	}
+}
+
+func TestClickTranslatesScrollOffset(t *testing.T) {
+	m := drive(tallForm(), tea.KeyMsg{Type: tea.KeyShiftTab})
+	ox, oy := m.formOrigin()
+	tops := fieldTops(m.fieldBoxes())
+
+	// The row just below the up indicator shows the first visible field.
+	first := 0
+	for tops[first+1] <= m.viewport.YOffset {
+		first++
+	}
+	if first == 0 {
+		t.Fatal("the first field is still in view")
+	}
+	m = drive(m, click(ox+2, oy+m.headerHeight()+1))
+	if m.focused != first {
+		t.Errorf("clicking the top row focused %d, want %d behind the scroll offset", m.focused, first)
+	}
+}
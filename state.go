@@ -0,0 +1,142 @@
+package cornice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// state holds preferences remembered across sessions.
+type state struct {
+	ShowPassword bool   `json:"show_password"`
+	Username     string `json:"username,omitempty"`
+}
+
+// statePath returns the location of the state file under the user's config
+// directory.
+func statePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cornice", "state.json"), nil
+}
+
+// stateMu serializes access to the state file within the process, so
+// concurrent updates don't lose each other's changes.
+var stateMu sync.Mutex
+
+// revealSeq numbers the show password saves in the order they were
+// requested; revealSaved is the latest one written, under stateMu.
+var (
+	revealSeq   atomic.Uint64
+	revealSaved uint64
+)
+
+// readState reads the saved state. A missing file yields the zero state.
+func readState() (state, error) {
+	var s state
+	path, err := statePath()
+	if err != nil {
+		return s, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return state{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// loadState reads the saved state. A missing or unreadable file yields the
+// zero state.
+func loadState() state {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	s, err := readState()
+	if err != nil {
+		log.Printf("state: %v", err)
+	}
+	return s
+}
+
+// saveState writes s to the state file, creating its directory if needed.
+// It writes a temporary file and renames it into place, so readers never
+// see a partial file.
+func saveState(s state) error {
+	path, err := statePath()
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "state-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// updateState applies update to the saved state and writes it back,
+// keeping the preferences it doesn't touch. A state file that can't be
+// parsed is left alone rather than overwritten with the zero state.
+func updateState(update func(*state)) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	return updateStateLocked(update)
+}
+
+func updateStateLocked(update func(*state)) error {
+	s, err := readState()
+	if err != nil {
+		return err
+	}
+	update(&s)
+	return saveState(s)
+}
+
+// saveShowPasswordCmd saves the show password preference in the
+// background. Saves can finish out of order, so one superseded by a later
+// toggle that is already written is dropped.
+func saveShowPasswordCmd(show bool) tea.Cmd {
+	seq := revealSeq.Add(1)
+	return func() tea.Msg {
+		stateMu.Lock()
+		defer stateMu.Unlock()
+		if seq < revealSaved {
+			return nil
+		}
+		revealSaved = seq
+		if err := updateStateLocked(func(s *state) { s.ShowPassword = show }); err != nil {
+			log.Printf("state: %v", err)
+		}
+		return nil
+	}
+}
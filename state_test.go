@@ -0,0 +1,139 @@
+package cornice
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var ctrlR = tea.KeyMsg{Type: tea.KeyCtrlR}
+
+// readStateFile returns the state file parsed, failing the test if it
+// isn't valid JSON.
+func readStateFile(t *testing.T) state {
+	t.Helper()
+	path, err := statePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s state
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatalf("state file is not valid JSON: %v\n%s", err, data)
+	}
+	return s
+}
+
+func TestRevealNotPersistedByDefault(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	if err := saveState(state{ShowPassword: true}); err != nil {
+		t.Fatal(err)
+	}
+	if m := initialModel(); m.showPassword {
+		t.Error("the saved reveal was applied without WithPersistentReveal")
+	}
+
+	// Ctrl+R reveals here, so a save would flip the stored false.
+	if err := saveState(state{}); err != nil {
+		t.Fatal(err)
+	}
+	m := drive(initialModel(), ctrlR)
+	if !m.showPassword {
+		t.Fatal("Ctrl+R didn't reveal the password")
+	}
+	if s := readStateFile(t); s.ShowPassword {
+		t.Error("Ctrl+R saved state without WithPersistentReveal")
+	}
+}
+
+func TestForceMaskOverridesSavedReveal(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	if err := saveState(state{ShowPassword: true}); err != nil {
+		t.Fatal(err)
+	}
+	for _, opts := range [][]Option{
+		{WithPersistentReveal(), WithForceMask()},
+		{WithForceMask(), WithPersistentReveal()},
+	} {
+		if m := initialModel(opts...); m.showPassword {
+			t.Error("the saved reveal undid WithForceMask")
+		}
+	}
+	if s := readStateFile(t); !s.ShowPassword {
+		t.Error("WithForceMask changed the saved preference")
+	}
+}
+
+func TestRevealPersisted(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	if err := saveState(state{ShowPassword: true, Username: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	m := initialModel(WithPersistentReveal())
+	if !m.showPassword {
+		t.Fatal("the saved reveal wasn't applied")
+	}
+	m = drive(m, ctrlR)
+	if m.showPassword {
+		t.Fatal("Ctrl+R didn't hide the password")
+	}
+	s := readStateFile(t)
+	if s.ShowPassword {
+		t.Error("the toggle wasn't saved")
+	}
+	if s.Username != "alice" {
+		t.Errorf("username = %q, want it kept as %q", s.Username, "alice")
+	}
+}
+
+func TestRevealSavesLatestToggle(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	// Five quick toggles end revealed; their saves run concurrently and
+	// in reverse, as if the first ones were slowest.
+	var cmds []tea.Cmd
+	show := false
+	for i := 0; i < 5; i++ {
+		show = !show
+		cmds = append(cmds, saveShowPasswordCmd(show))
+	}
+	var wg sync.WaitGroup
+	for i := len(cmds) - 1; i >= 0; i-- {
+		wg.Add(1)
+		go func(cmd tea.Cmd) {
+			defer wg.Done()
+			cmd()
+		}(cmds[i])
+	}
+	wg.Wait()
+
+	if s := readStateFile(t); s.ShowPassword != show {
+		t.Errorf("ShowPassword = %t, want the last toggle %t", s.ShowPassword, show)
+	}
+}
+
+func TestUpdateStateKeepsUnparsableFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	if err := saveState(state{}); err != nil {
+		t.Fatal(err)
+	}
+	path, _ := statePath()
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := updateState(func(s *state) { s.ShowPassword = true }); err == nil {
+		t.Error("updateState overwrote an unparsable state file")
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "{not json" {
+		t.Errorf("state file = %q, want it untouched", data)
+	}
+}
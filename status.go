@@ -0,0 +1,84 @@
+package cornice
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	validityOK      = "●"
+	validityEmpty   = "○"
+	validityInvalid = "✕"
+)
+
+var statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+
+// fieldValidity returns the mini map indicator for each field, in order.
+func (m model) fieldValidity() []string {
+	fs := m.fields()
+	marks := make([]string, len(fs))
+	for i, f := range fs {
+		switch {
+		case m.fieldValue(f) == "":
+			marks[i] = validityEmpty
+		case m.fieldError(f) != "":
+			marks[i] = validityInvalid
+		default:
+			marks[i] = validityOK
+		}
+	}
+	return marks
+}
+
+// editingLabel names the focused field for the status bar.
+func (m model) editingLabel() string {
+	return "Editing: " + m.fieldTitle(m.focusedField())
+}
+
+// statusBar renders the status line beneath the form, or "" when there is
+// nothing to show. The validity marks come first so clicks on them map
+// straight to fields.
+func (m model) statusBar() string {
+	var items []string
+	if m.showStatusBar {
+		items = append(items, strings.Join(m.fieldValidity(), " "), m.editingLabel())
+	}
+	if m.mouseOff() {
+		items = append(items, "mouse off")
+	}
+	return m.style(statusStyle).Render(strings.Join(items, "  "))
+}
+
+// mouseOff reports whether the user has turned the mouse off, with
+// WithMouse or Ctrl+T. It stays quiet when inline mode or the terminal
+// rules the mouse out, since there is nothing to turn back on.
+func (m model) mouseOff() bool {
+	return !m.mouseEnabled && m.mouseAvailable()
+}
+
+// statusHeight is the number of rows the status bar takes up.
+func (m model) statusHeight() int {
+	if !m.showStatusBar && !m.mouseOff() {
+		return 0
+	}
+	return 1
+}
+
+// statusRow is the screen row the status bar is rendered on.
+func (m model) statusRow() int {
+	return m.headerHeight() + lipgloss.Height(m.scrolledForm()) + m.belowFieldsHeight()
+}
+
+// statusFieldAt maps a click on the status bar to the field whose
+// indicator is under it. Indicators are one column wide, a space apart.
+func (m model) statusFieldAt(x, y int) (int, bool) {
+	if !m.showStatusBar || m.compact() || y != m.statusRow() || x < 0 || x%2 != 0 {
+		return 0, false
+	}
+	i := x / 2
+	if i >= m.fieldCount() {
+		return 0, false
+	}
+	return i, true
+}
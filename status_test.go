@@ -0,0 +1,67 @@
+package cornice
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestStatusBarFollowsFocus(t *testing.T) {
+	m := initialModel(WithStatusBar(), WithColorMode(ColorNever))
+	m = drive(m, tea.WindowSizeMsg{Width: 80, Height: 24})
+	if !strings.Contains(m.View(), "Editing: Username") {
+		t.Fatalf("the status bar doesn't name the username:\n%s", m.View())
+	}
+
+	m = drive(m, tea.KeyMsg{Type: tea.KeyTab})
+	if v := m.View(); !strings.Contains(v, "Editing: Password") {
+		t.Errorf("the status bar doesn't follow Tab:\n%s", v)
+	}
+	m = drive(m, click(40, fieldRow(m, 0)))
+	if v := m.View(); !strings.Contains(v, "Editing: Username") {
+		t.Errorf("the status bar doesn't follow a click:\n%s", v)
+	}
+
+	m = drive(m, script(keys("alice"), []tea.Msg{tea.KeyMsg{Type: tea.KeyEnter}})...)
+	if !m.done || strings.Contains(m.View(), "Editing:") {
+		t.Errorf("done = %v; want the status bar gone once done:\n%s", m.done, m.View())
+	}
+}
+
+func TestFieldValidity(t *testing.T) {
+	m := initialModel(WithUsernameLength(3, 0))
+	m = drive(m, keys("al")...)
+	if got := strings.Join(m.fieldValidity(), " "); got != validityInvalid+" "+validityEmpty {
+		t.Errorf("validity = %q with a short username and no password", got)
+	}
+	m = drive(m, keys("i")...)
+	if got := m.fieldValidity()[0]; got != validityOK {
+		t.Errorf("username validity = %q once valid, want %q", got, validityOK)
+	}
+}
+
+func TestMouseOffOnlyWhenTurnedOff(t *testing.T) {
+	t.Setenv("TERM", "xterm-256color")
+	size := tea.WindowSizeMsg{Width: 80, Height: 24}
+
+	m := drive(initialModel(WithMouse(false), WithColorMode(ColorNever)), size)
+	if !strings.Contains(m.View(), "mouse off") {
+		t.Errorf("WithMouse(false) isn't shown:\n%s", m.View())
+	}
+	m = drive(initialModel(WithColorMode(ColorNever)), size, tea.KeyMsg{Type: tea.KeyCtrlT})
+	if !strings.Contains(m.View(), "mouse off") {
+		t.Errorf("Ctrl+T turning the mouse off isn't shown:\n%s", m.View())
+	}
+
+	m = drive(initialModel(WithInline(), WithColorMode(ColorNever)), size)
+	if strings.Contains(m.View(), "mouse off") {
+		t.Errorf("inline mode shows mouse off:\n%s", m.View())
+	}
+	t.Setenv("TERM", "dumb")
+	m = drive(initialModel(WithColorMode(ColorNever)), size)
+	m.mouseEnabled = false // as runProgram does when the terminal can't report the mouse
+	if strings.Contains(m.View(), "mouse off") || m.statusHeight() != 0 {
+		t.Errorf("a terminal without mouse support shows mouse off:\n%s", m.View())
+	}
+}
@@ -0,0 +1,93 @@
+package cornice
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// readCredentials reads the username from the first line of r and the
+// password from the second, then the one-time code from the third when
+// totp is set. Missing lines after the username yield empty values. When
+// r is a terminal the password is read without echo.
+func readCredentials(r io.Reader, totp bool) (Credentials, error) {
+	var c Credentials
+	br := bufio.NewReader(r)
+	username, err := readLine(br)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return c, errors.New("cornice: no username on input")
+		}
+		return c, err
+	}
+	c.Username = username
+	if f, ok := r.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		pw, err := term.ReadPassword(int(f.Fd()))
+		if err != nil {
+			return Credentials{}, err
+		}
+		c.Password = string(pw)
+	} else if c.Password, err = readLine(br); err != nil && !errors.Is(err, io.EOF) {
+		return Credentials{}, err
+	}
+	if totp {
+		if c.TOTP, err = readLine(br); err != nil && !errors.Is(err, io.EOF) {
+			return Credentials{}, err
+		}
+	}
+	return c, nil
+}
+
+// readLine reads a line without its terminator. A final line without one
+// is returned as is; io.EOF is only returned when nothing was read.
+func readLine(br *bufio.Reader) (string, error) {
+	line, err := br.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// completeFromInput fills the form from input without running the TUI and
+// completes it as if it had been submitted: fields are validated, values
+// over their character limit are rejected rather than cut short, the
+// username transform is applied and the result is written to out.
+func (m model) completeFromInput(input io.Reader, out io.Writer) (model, error) {
+	c, err := readCredentials(input, m.totp)
+	if err != nil {
+		return m, err
+	}
+	for _, v := range []struct {
+		f     field
+		value string
+	}{
+		{fieldUsername, c.Username},
+		{fieldPassword, c.Password},
+		{fieldConfirm, c.Password},
+		{fieldTOTP, c.TOTP},
+	} {
+		in := m.input(v.f)
+		if in.CharLimit > 0 && utf8.RuneCountInString(v.value) > in.CharLimit {
+			return m, fmt.Errorf("cornice: %s is longer than %d characters", m.fieldName(v.f), in.CharLimit)
+		}
+		in.SetValue(v.value)
+	}
+	for _, f := range m.fields() {
+		if err := m.fieldError(f); err != "" {
+			return m, fmt.Errorf("cornice: %s", err)
+		}
+	}
+	m.usernameInput.SetValue(m.usernameValue())
+	m.done = true
+	if m.resultMode != resultText {
+		return m, m.printResult(out)
+	}
+	_, err = io.WriteString(out, m.resultView())
+	return m, err
+}
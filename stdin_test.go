@@ -0,0 +1,65 @@
+package cornice
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadCredentials(t *testing.T) {
+	tests := []struct {
+		input string
+		totp  bool
+		want  Credentials
+	}{
+		{"alice\nsecret\n", false, Credentials{Username: "alice", Password: "secret"}},
+		{"alice\r\nsecret\r\n", false, Credentials{Username: "alice", Password: "secret"}},
+		{"alice\nsecret", false, Credentials{Username: "alice", Password: "secret"}},
+		{"alice\n", false, Credentials{Username: "alice"}},
+		{"alice\nsecret\n123456\n", false, Credentials{Username: "alice", Password: "secret"}},
+		{"alice\nsecret\n123456\n", true, Credentials{Username: "alice", Password: "secret", TOTP: "123456"}},
+		{"alice\nsecret\n", true, Credentials{Username: "alice", Password: "secret"}},
+	}
+	for _, tt := range tests {
+		got, err := readCredentials(strings.NewReader(tt.input), tt.totp)
+		if err != nil {
+			t.Errorf("readCredentials(%q): %v", tt.input, err)
+			continue
+		}
+		if got.Username != tt.want.Username || got.Password != tt.want.Password || got.TOTP != tt.want.TOTP {
+			t.Errorf("readCredentials(%q, %v) = %+v, want %+v", tt.input, tt.totp, got, tt.want)
+		}
+	}
+}
+
+func TestReadCredentialsEmptyInput(t *testing.T) {
+	if _, err := readCredentials(strings.NewReader(""), false); err == nil {
+		t.Error("empty input accepted")
+	}
+}
+
+func TestCompleteFromInputValidates(t *testing.T) {
+	var out strings.Builder
+	_, err := initialModel(WithPasswordLength(8, 0)).completeFromInput(strings.NewReader("alice\nshort\n"), &out)
+	if err == nil {
+		t.Fatal("a too short password was accepted")
+	}
+	if out.Len() != 0 {
+		t.Errorf("wrote %q for rejected input", out.String())
+	}
+}
+
+func TestCompleteFromInputRejectsOverLimit(t *testing.T) {
+	var out strings.Builder
+	_, err := initialModel(WithCharLimits(0, 4)).completeFromInput(strings.NewReader("alice\nsecret\n"), &out)
+	if err == nil || !strings.Contains(err.Error(), "password") {
+		t.Fatalf("err = %v, want the long password rejected", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("wrote %q for rejected input", out.String())
+	}
+
+	m, err := initialModel(WithCharLimits(5, 6)).completeFromInput(strings.NewReader("alice\nsecret\n"), &out)
+	if err != nil || m.passwordInput.Value() != "secret" {
+		t.Errorf("values at the limit: password %q, err %v", m.passwordInput.Value(), err)
+	}
+}
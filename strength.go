@@ -0,0 +1,122 @@
+package cornice
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// strength is a rough rating of how hard a password is to guess.
+type strength int
+
+const (
+	strengthWeak strength = iota
+	strengthMedium
+	strengthStrong
+)
+
+var strengthColors = map[strength]lipgloss.Color{
+	strengthWeak:   "#FF5F5F",
+	strengthMedium: "#FFAF00",
+	strengthStrong: "#00C853",
+}
+
+// String returns the strength's label.
+func (s strength) String() string {
+	switch s {
+	case strengthMedium:
+		return "Medium"
+	case strengthStrong:
+		return "Strong"
+	}
+	return "Weak"
+}
+
+// passwordStrength rates pw by its length and the variety of character
+// classes it mixes: lower case, upper case, digits and everything else.
+// Variety only counts once the password has some length.
+func passwordStrength(pw string) strength {
+	var lower, upper, digit, other bool
+	for _, r := range pw {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+	classes := 0
+	for _, has := range []bool{lower, upper, digit, other} {
+		if has {
+			classes++
+		}
+	}
+
+	score := 0
+	n := utf8.RuneCountInString(pw)
+	if n >= 8 {
+		score++
+	}
+	if n >= 12 {
+		score++
+	}
+	if classes >= 3 && n >= 8 {
+		score++
+	}
+	switch {
+	case score == 0:
+		return strengthWeak
+	case score < 3:
+		return strengthMedium
+	}
+	return strengthStrong
+}
+
+// strengthMeter renders the live strength of the password beneath its
+// input, or "" when the meter is off or the password is empty.
+func (m model) strengthMeter() string {
+	pw := m.fieldValue(fieldPassword)
+	if !m.showStrength || pw == "" {
+		return ""
+	}
+	s := passwordStrength(pw)
+	bar := strings.Repeat("■", int(s)+1) + strings.Repeat("□", int(strengthStrong-s))
+	return m.style(lipgloss.NewStyle()).Foreground(strengthColors[s]).Render(bar + " " + s.String())
+}
+
+// PasswordSummary selects how the result screen describes the password.
+type PasswordSummary int
+
+const (
+	// SummaryHidden shows that a password was entered, without revealing
+	// anything about it.
+	SummaryHidden PasswordSummary = iota
+	// SummaryLength shows the password's length.
+	SummaryLength
+	// SummaryStrength shows the password's strength label.
+	SummaryStrength
+	// SummaryNone leaves the password out.
+	SummaryNone
+)
+
+// ParsePasswordSummary parses "hidden", "length", "strength" or "none".
+func ParsePasswordSummary(s string) (PasswordSummary, error) {
+	switch s {
+	case "hidden":
+		return SummaryHidden, nil
+	case "length":
+		return SummaryLength, nil
+	case "strength":
+		return SummaryStrength, nil
+	case "none":
+		return SummaryNone, nil
+	}
+	return SummaryHidden, fmt.Errorf("unknown password summary %q", s)
+}
@@ -0,0 +1,116 @@
+package cornice
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPasswordStrength(t *testing.T) {
+	tests := []struct {
+		pw   string
+		want strength
+	}{
+		{"", strengthWeak},
+		{"abc", strengthWeak},
+		{"Ab1!", strengthWeak},
+		{"abcdefgh", strengthMedium},
+		{"abcdefghijkl", strengthMedium},
+		{"Abcdefg1", strengthMedium},
+		{"Abcdefghij1!", strengthStrong},
+	}
+	for _, tt := range tests {
+		if got := passwordStrength(tt.pw); got != tt.want {
+			t.Errorf("passwordStrength(%q) = %v, want %v", tt.pw, got, tt.want)
+		}
+	}
+}
+
+func TestResultPasswordSummary(t *testing.T) {
+	const pw = "Abcdefghij1!"
+	tests := []struct {
+		summary PasswordSummary
+		want    string
+		absent  []string
+	}{
+		{SummaryHidden, "Password: (hidden)", []string{pw, "length", "strength"}},
+		{SummaryLength, "Password length: 12", []string{pw, "strength"}},
+		{SummaryStrength, "Password strength: Strong", []string{pw, "length"}},
+		{SummaryNone, "", []string{pw, "Password"}},
+	}
+	for _, tt := range tests {
+		m := initialModel(WithPasswordSummary(tt.summary))
+		m.passwordInput.SetValue(pw)
+		view := m.resultView()
+		if !strings.Contains(view, tt.want) {
+			t.Errorf("summary %d: result lacks %q:\n%s", tt.summary, tt.want, view)
+		}
+		for _, s := range tt.absent {
+			if strings.Contains(view, s) {
+				t.Errorf("summary %d: result shows %q:\n%s", tt.summary, s, view)
+			}
+		}
+	}
+}
+
+func TestParsePasswordSummary(t *testing.T) {
+	if got, err := ParsePasswordSummary("strength"); err != nil || got != SummaryStrength {
+		t.Errorf(`ParsePasswordSummary("strength") = %v, %v`, got, err)
+	}
+	if _, err := ParsePasswordSummary("full"); err == nil {
+		t.Error(`ParsePasswordSummary("full") succeeded`)
+	}
+}
+
+func TestStrengthMeterUpdatesPerKeystroke(t *testing.T) {
+	m := initialModel(WithStrengthMeter(), WithColorMode(ColorNever))
+	m = drive(m, tea.WindowSizeMsg{Width: 80, Height: 30}, tea.KeyMsg{Type: tea.KeyTab})
+	if m.strengthMeter() != "" {
+		t.Error("the meter shows for an empty password")
+	}
+	for _, step := range []struct{ typed, want string }{
+		{"abc", "Weak"},
+		{"defgh", "Medium"},
+		{"ijkL1", "Strong"},
+	} {
+		m = drive(m, keys(step.typed)...)
+		view := m.View()
+		if !strings.Contains(view, step.want) {
+			t.Errorf("after %q the view lacks %q", m.passwordInput.Value(), step.want)
+		}
+		if strings.Index(view, step.want) < strings.Index(view, "Password") {
+			t.Errorf("the %s label is above the password box", step.want)
+		}
+	}
+}
+
+func TestShortPasswordBlocksSubmit(t *testing.T) {
+	m := initialModel(WithPasswordLength(8, 0))
+	m = drive(m, script(keys("alice"), []tea.Msg{tea.KeyMsg{Type: tea.KeyTab}}, keys("1234567"), []tea.Msg{tea.KeyMsg{Type: tea.KeyEnter}})...)
+	if m.done {
+		t.Fatal("a 7-character password was accepted")
+	}
+	if err := m.err(fieldPassword); err != "Password must be at least 8 characters" {
+		t.Errorf("password error = %q", err)
+	}
+	m = drive(m, script(keys("8"), []tea.Msg{tea.KeyMsg{Type: tea.KeyEnter}})...)
+	if !m.done {
+		t.Errorf("an 8-character password was rejected: %q", m.err(fieldPassword))
+	}
+}
+
+func TestResultHidesPasswordLengthByDefault(t *testing.T) {
+	submit := script(keys("alice"), []tea.Msg{tea.KeyMsg{Type: tea.KeyTab}}, keys("secret12"), []tea.Msg{tea.KeyMsg{Type: tea.KeyEnter}})
+
+	m := drive(initialModel(WithColorMode(ColorNever)), submit...)
+	view := m.View()
+	if !strings.Contains(view, "Password: (hidden)") || strings.Contains(view, "8") {
+		t.Errorf("default result leaks the password length:\n%s", view)
+	}
+
+	m = drive(initialModel(WithColorMode(ColorNever), WithPasswordSummary(SummaryLength)), submit...)
+	if view := m.View(); !strings.Contains(view, "Password length: 8") {
+		t.Errorf("result lacks the length when asked for:\n%s", view)
+	}
+}
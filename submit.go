@@ -0,0 +1,76 @@
+package cornice
+
+import (
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// submitResultMsg carries the submit callback's verdict back to Update.
+type submitResultMsg struct {
+	err error
+}
+
+// authenticate starts the submit callback off the UI goroutine, showing
+// the spinner with the inputs disabled until submitResultMsg arrives.
+func (m model) authenticate() (model, tea.Cmd) {
+	m.submitErr = ""
+	m.submitting = true
+	submit, username, password := m.onSubmit, m.usernameValue(), m.fieldValue(fieldPassword)
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		return submitResultMsg{err: submit(username, password)}
+	})
+}
+
+// updateSubmitting handles messages while the submit callback runs. Input
+// is ignored except Ctrl+C.
+func (m model) updateSubmitting(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case submitResultMsg:
+		return m.handleSubmitResult(msg)
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+	case tea.WindowSizeMsg:
+		m = m.resize(msg)
+	}
+	return m, nil
+}
+
+// handleSubmitResult records the callback's verdict: an error is shown
+// and counted towards the lockout, success completes the form.
+func (m model) handleSubmitResult(msg submitResultMsg) (tea.Model, tea.Cmd) {
+	m.submitting = false
+	if msg.err != nil {
+		m.submitErr = msg.err.Error()
+		m, cmd := m.recordFailure()
+		return m.scrollToFocus(), cmd
+	}
+	return m.recordSuccess().accepted()
+}
+
+// submitErrLine renders the spinner while the submit callback runs, then
+// the error it returned, word-wrapped to the width of the boxes, or ""
+// when there is none.
+func (m model) submitErrLine() string {
+	if m.submitting {
+		return m.spinner.View() + "Submitting…"
+	}
+	if m.submitErr == "" {
+		return ""
+	}
+	return m.style(errorStyle).Width(m.boxWidth() + 2).Render(m.submitErr)
+}
+
+// submitErrHeight is the number of rows the submit line takes up.
+func (m model) submitErrHeight() int {
+	if !m.submitting && m.submitErr == "" {
+		return 0
+	}
+	return lipgloss.Height(m.submitErrLine())
+}
@@ -0,0 +1,102 @@
+package cornice
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestSubmittingState(t *testing.T) {
+	called := false
+	m := initialModel(WithColorMode(ColorNever), WithSubmit(func(username, password string) error {
+		called = true
+		return nil
+	}))
+	m = drive(m, script([]tea.Msg{tea.WindowSizeMsg{Width: 80, Height: 24}}, keys("alice"))...)
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(model)
+	if !m.submitting || cmd == nil {
+		t.Fatalf("submitting = %v after Enter, want the callback started", m.submitting)
+	}
+	if called {
+		t.Error("Update ran the callback instead of returning it as a command")
+	}
+	if !strings.Contains(m.View(), "Submitting…") {
+		t.Error("the view lacks the spinner line while submitting")
+	}
+	m = drive(m, keys("x")...)
+	if v := m.usernameInput.Value(); v != "alice" {
+		t.Errorf("username = %q, want input ignored while submitting", v)
+	}
+
+	m = drive(m, submitResultMsg{err: errors.New("server down")})
+	if m.submitting || m.done {
+		t.Errorf("submitting = %v, done = %v after an error; want back to editing", m.submitting, m.done)
+	}
+	if !strings.Contains(m.View(), "server down") {
+		t.Error("the view lacks the callback's error")
+	}
+
+	m = drive(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if !called || m.submitting || !m.done {
+		t.Errorf("called = %v, submitting = %v, done = %v; want the retry accepted", called, m.submitting, m.done)
+	}
+}
+
+func TestSubmitErrorWraps(t *testing.T) {
+	m := initialModel(WithColorMode(ColorNever))
+	m = drive(m, tea.WindowSizeMsg{Width: 80, Height: 24})
+	long := strings.Repeat("the directory server refused the bind ", 4)
+	m.submitting = true
+	m = drive(m, submitResultMsg{err: errors.New(long)})
+
+	lines := strings.Split(m.submitErrLine(), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("a %d-column error rendered on one line at box width %d", len(long), m.boxWidth())
+	}
+	for _, l := range lines {
+		if w := lipgloss.Width(l); w > m.boxWidth()+2 {
+			t.Errorf("line %q is %d wide, want at most %d", l, w, m.boxWidth()+2)
+		}
+	}
+	if got := m.submitErrHeight(); got != len(lines) {
+		t.Errorf("submitErrHeight = %d, want the %d wrapped lines", got, len(lines))
+	}
+
+	m = drive(m, keys("a")...)
+	if m.submitErr != "" {
+		t.Errorf("submitErr = %q after a key press, want it cleared", m.submitErr)
+	}
+}
+
+func TestEnterSubmitsByDefault(t *testing.T) {
+	m := drive(initialModel(), script(keys("alice"), []tea.Msg{tea.KeyMsg{Type: tea.KeyEnter}})...)
+	if !m.done {
+		t.Error("Enter didn't submit")
+	}
+	m = drive(initialModel(), script(keys("alice"), []tea.Msg{tea.KeyMsg{Type: tea.KeyCtrlS}})...)
+	if m.done {
+		t.Error("Ctrl+S submitted without WithEnterAdvances")
+	}
+}
+
+func TestEnterAdvances(t *testing.T) {
+	for _, submitKey := range []tea.KeyType{tea.KeyCtrlS, tea.KeyCtrlD} {
+		m := drive(initialModel(WithEnterAdvances()), script(keys("alice"), []tea.Msg{tea.KeyMsg{Type: tea.KeyEnter}})...)
+		if m.done || m.focusedField() != fieldPassword {
+			t.Fatalf("done = %v, focused %v after Enter; want the password focused", m.done, m.focusedField())
+		}
+		m = drive(m, tea.KeyMsg{Type: tea.KeyEnter})
+		if m.done || m.focusedField() != fieldUsername {
+			t.Fatalf("done = %v, focused %v after Enter on the last field; want focus wrapped", m.done, m.focusedField())
+		}
+		m = drive(m, tea.KeyMsg{Type: submitKey})
+		if !m.done {
+			t.Errorf("%v didn't submit", submitKey)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package cornice
+
+import (
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
+)
+
+const (
+	defaultWidth  = 80
+	defaultHeight = 24
+
+	// sizeProbeDelay is how long to wait for Bubble Tea's initial
+	// WindowSizeMsg before probing the terminal ourselves.
+	sizeProbeDelay = 100 * time.Millisecond
+)
+
+// noMouseTerms are terminals known not to understand mouse reporting.
+var noMouseTerms = map[string]bool{
+	"":       true,
+	"dumb":   true,
+	"linux":  true,
+	"cons25": true,
+	"vt52":   true,
+	"vt100":  true,
+	"vt102":  true,
+	"vt220":  true,
+}
+
+// supportsMouse reports whether the terminal named by TERM likely supports
+// mouse reporting, so enabling it won't leak escape sequences as input.
+func supportsMouse() bool {
+	return !noMouseTerms[os.Getenv("TERM")]
+}
+
+// mouseAvailable reports whether Ctrl+T may turn the mouse on: the
+// terminal must support it, and inline mode never uses it since the
+// form's screen position is unknown.
+func (m model) mouseAvailable() bool {
+	return !m.inline && supportsMouse()
+}
+
+// sizeProbeMsg carries a terminal size obtained by probing, used only when
+// no WindowSizeMsg has arrived yet.
+type sizeProbeMsg struct {
+	width, height int
+}
+
+// probeSizeAfter returns a command that probes the terminal size after d.
+func (m model) probeSizeAfter(d time.Duration) tea.Cmd {
+	debug := m.run.debug
+	return m.tick(d, func(time.Time) tea.Msg {
+		width, height := terminalSizeOrDefault(debug)
+		return sizeProbeMsg{width: width, height: height}
+	})
+}
+
+// sizeCache remembers the last known terminal size across forms in the
+// same process, so repeated prompts don't probe the terminal again.
+var sizeCache struct {
+	sync.Mutex
+	width, height int
+}
+
+// rememberSize records a size reported by the terminal, replacing any
+// earlier one.
+func rememberSize(width, height int) {
+	sizeCache.Lock()
+	defer sizeCache.Unlock()
+	sizeCache.width, sizeCache.height = width, height
+}
+
+// resize applies a new terminal size to the form and remembers it for
+// later forms.
+func (m model) resize(msg tea.WindowSizeMsg) model {
+	m.width, m.height = msg.Width, msg.Height
+	rememberSize(msg.Width, msg.Height)
+	return m.scrollToFocus()
+}
+
+// terminalSizeOrDefault returns the terminal size, or 80x24 when it can't
+// be determined. A size already known to the process is reused, and a
+// probed one is remembered. The first WindowSizeMsg corrects a wrong
+// guess, so the failure is only logged when debug is set.
+func terminalSizeOrDefault(debug bool) (width, height int) {
+	sizeCache.Lock()
+	defer sizeCache.Unlock()
+	if sizeCache.width > 0 && sizeCache.height > 0 {
+		return sizeCache.width, sizeCache.height
+	}
+	width, height, err := getTerminalSize()
+	if err != nil || width <= 0 || height <= 0 {
+		if debug {
+			log.Printf("terminal size unavailable (%v), using %dx%d", err, defaultWidth, defaultHeight)
+		}
+		return defaultWidth, defaultHeight
+	}
+	sizeCache.width, sizeCache.height = width, height
+	return width, height
+}
+
+// getTerminalSize reads the size of the terminal on stdout, falling back
+// to $COLUMNS and $LINES, and finally to 80x24. The error from querying
+// the terminal is returned alongside the default when nothing else works.
+func getTerminalSize() (width, height int, err error) {
+	width, height, err = term.GetSize(int(os.Stdout.Fd()))
+	if err == nil && width > 0 && height > 0 {
+		return width, height, nil
+	}
+	if w, h, ok := envTerminalSize(); ok {
+		return w, h, nil
+	}
+	return defaultWidth, defaultHeight, err
+}
+
+// envTerminalSize reads the terminal size from $COLUMNS and $LINES.
+func envTerminalSize() (width, height int, ok bool) {
+	width, err := strconv.Atoi(strings.TrimSpace(os.Getenv("COLUMNS")))
+	if err != nil || width <= 0 {
+		return 0, 0, false
+	}
+	height, err = strconv.Atoi(strings.TrimSpace(os.Getenv("LINES")))
+	if err != nil || height <= 0 {
+		return 0, 0, false
+	}
+	return width, height, true
+}
+
+// clearSequence erases the screen and homes the cursor.
+const clearSequence = "\x1b[2J\x1b[H"
+
+// clearTerminal clears the screen on w. Terminals that understand ANSI
+// get clearSequence directly; others fall back to the platform's clear
+// command. Writers other than files are assumed to be captured output
+// and get the sequence.
+func clearTerminal(w io.Writer) {
+	f, ok := w.(*os.File)
+	if !ok {
+		_, _ = io.WriteString(w, clearSequence)
+		return
+	}
+	if !term.IsTerminal(int(f.Fd())) {
+		return
+	}
+	if supportsANSI() {
+		_, _ = io.WriteString(f, clearSequence)
+		return
+	}
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/c", "cls")
+	} else {
+		cmd = exec.Command("clear")
+	}
+	cmd.Stdout = f
+	_ = cmd.Run()
+}
+
+// supportsANSI reports whether the terminal likely honours ANSI escape
+// sequences. The Windows console only does once virtual terminal
+// processing is enabled, which Bubble Tea doesn't do until it starts.
+func supportsANSI() bool {
+	return runtime.GOOS != "windows" && os.Getenv("TERM") != "dumb"
+}
@@ -0,0 +1,220 @@
+package cornice
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMouseToggle(t *testing.T) {
+	ctrlT := tea.KeyMsg{Type: tea.KeyCtrlT}
+	tests := []struct {
+		name    string
+		term    string
+		opts    []Option
+		toggles bool
+	}{
+		{"xterm", "xterm-256color", nil, true},
+		{"dumb terminal", "dumb", nil, false},
+		{"inline", "xterm-256color", []Option{WithInline()}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TERM", tt.term)
+			m := initialModel(tt.opts...)
+			before := m.mouseEnabled
+			next, cmd := m.Update(ctrlT)
+			m = next.(model)
+			toggled := m.mouseEnabled != before
+			if toggled != tt.toggles {
+				t.Errorf("Ctrl+T toggled = %v, want %v", toggled, tt.toggles)
+			}
+			if !toggled && cmd != nil {
+				t.Error("ignored Ctrl+T still sent a mouse command")
+			}
+		})
+	}
+}
+
+// withoutTerminal points os.Stdout at a pipe for the rest of the test, so
+// the size can't be read from it.
+func withoutTerminal(t *testing.T) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := os.Stdout
+	os.Stdout = w
+	t.Cleanup(func() {
+		os.Stdout = old
+		r.Close()
+		w.Close()
+	})
+}
+
+func TestTerminalSizeFromEnvironment(t *testing.T) {
+	withoutTerminal(t)
+	t.Setenv("COLUMNS", "132")
+	t.Setenv("LINES", " 43 ")
+	w, h, err := getTerminalSize()
+	if err != nil || w != 132 || h != 43 {
+		t.Errorf("getTerminalSize() = %d, %d, %v; want 132, 43 from the environment", w, h, err)
+	}
+}
+
+func TestEnvTerminalSizeRejectsBadValues(t *testing.T) {
+	for _, tt := range []struct{ columns, lines string }{
+		{"", "24"},
+		{"80", ""},
+		{"wide", "24"},
+		{"80", "-1"},
+		{"0", "24"},
+	} {
+		t.Setenv("COLUMNS", tt.columns)
+		t.Setenv("LINES", tt.lines)
+		if w, h, ok := envTerminalSize(); ok {
+			t.Errorf("COLUMNS=%q LINES=%q gave %dx%d, want no size", tt.columns, tt.lines, w, h)
+		}
+	}
+}
+
+// withSizeCache sets the process-wide size cache for the rest of the test.
+func withSizeCache(t testing.TB, width, height int) {
+	t.Helper()
+	sizeCache.Lock()
+	oldWidth, oldHeight := sizeCache.width, sizeCache.height
+	sizeCache.width, sizeCache.height = width, height
+	sizeCache.Unlock()
+	t.Cleanup(func() {
+		sizeCache.Lock()
+		sizeCache.width, sizeCache.height = oldWidth, oldHeight
+		sizeCache.Unlock()
+	})
+}
+
+func TestTerminalSizeDefaultsWhenUnknown(t *testing.T) {
+	withoutTerminal(t)
+	withSizeCache(t, 0, 0)
+	t.Setenv("COLUMNS", "")
+	t.Setenv("LINES", "")
+
+	if w, h, err := getTerminalSize(); err == nil || w != defaultWidth || h != defaultHeight {
+		t.Errorf("getTerminalSize() = %d, %d, %v; want the 80x24 default and the error", w, h, err)
+	}
+
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	if w, h := terminalSizeOrDefault(false); w != defaultWidth || h != defaultHeight {
+		t.Errorf("terminalSizeOrDefault() = %dx%d, want 80x24", w, h)
+	}
+	if logged.Len() != 0 {
+		t.Errorf("logged %q without debug", logged.String())
+	}
+	terminalSizeOrDefault(true)
+	if !strings.Contains(logged.String(), "using 80x24") {
+		t.Errorf("logged %q with debug, want the fallback reported", logged.String())
+	}
+	if sizeCache.width != 0 || sizeCache.height != 0 {
+		t.Error("the default size was cached as if it were real")
+	}
+}
+
+func TestSizeCacheReusedUntilResize(t *testing.T) {
+	withoutTerminal(t)
+	withSizeCache(t, 100, 30)
+	t.Setenv("COLUMNS", "132")
+	t.Setenv("LINES", "43")
+
+	if w, h := terminalSizeOrDefault(false); w != 100 || h != 30 {
+		t.Errorf("terminalSizeOrDefault() = %dx%d, want the cached 100x30", w, h)
+	}
+	m := initialModel().resize(tea.WindowSizeMsg{Width: 120, Height: 40})
+	if m.width != 120 || m.height != 40 {
+		t.Errorf("model size = %dx%d after resize, want 120x40", m.width, m.height)
+	}
+	if w, h := terminalSizeOrDefault(false); w != 120 || h != 40 {
+		t.Errorf("terminalSizeOrDefault() = %dx%d after a resize, want the new 120x40", w, h)
+	}
+}
+
+func TestSizeProbeCached(t *testing.T) {
+	withoutTerminal(t)
+	withSizeCache(t, 0, 0)
+	t.Setenv("COLUMNS", "132")
+	t.Setenv("LINES", "43")
+	terminalSizeOrDefault(false)
+
+	// The environment changes, but the probed size is remembered.
+	t.Setenv("COLUMNS", "90")
+	if w, h := terminalSizeOrDefault(false); w != 132 || h != 43 {
+		t.Errorf("terminalSizeOrDefault() = %dx%d, want the first probe's 132x43", w, h)
+	}
+}
+
+// BenchmarkTerminalSize compares probing the terminal on every lookup with
+// the cached lookup repeated forms use. The cached one makes no syscalls.
+func BenchmarkTerminalSize(b *testing.B) {
+	b.Run("probe", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			getTerminalSize()
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		withSizeCache(b, 80, 24)
+		for i := 0; i < b.N; i++ {
+			terminalSizeOrDefault(false)
+		}
+	})
+}
+
+func TestClearTerminalWritesANSI(t *testing.T) {
+	var buf bytes.Buffer
+	clearTerminal(&buf)
+	if got := buf.String(); got != clearSequence {
+		t.Errorf("clearTerminal wrote %q, want %q", got, clearSequence)
+	}
+}
+
+func TestClearTerminalSkipsNonTerminalFiles(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	clearTerminal(w)
+	w.Close()
+	got, _ := io.ReadAll(r)
+	if len(got) != 0 {
+		t.Errorf("clearTerminal wrote %q to a pipe, want nothing", got)
+	}
+}
+
+func TestScreenMode(t *testing.T) {
+	tests := []struct {
+		name             string
+		opts             []Option
+		altScreen, clear bool
+	}{
+		{"default", nil, false, true},
+		{"alt screen", []Option{WithAltScreen()}, true, false},
+		{"inline", []Option{WithInline()}, false, false},
+		{"inline wins", []Option{WithInline(), WithAltScreen()}, false, false},
+		{"other output", []Option{WithOutput(&bytes.Buffer{})}, false, false},
+		{"stdout output", []Option{WithOutput(os.Stdout)}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alt, clear := initialModel(tt.opts...).screenMode()
+			if alt != tt.altScreen || clear != tt.clear {
+				t.Errorf("screenMode() = %v, %v; want %v, %v", alt, clear, tt.altScreen, tt.clear)
+			}
+		})
+	}
+}
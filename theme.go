@@ -0,0 +1,132 @@
+package cornice
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Theme is the set of styles the form is drawn with.
+type Theme struct {
+	// Focused and Blurred style the border of the focused and the other
+	// field boxes.
+	Focused lipgloss.Style
+	Blurred lipgloss.Style
+	// MonoFocusBorder replaces the focused border when the terminal has no
+	// color support, so focus doesn't rely on color alone.
+	MonoFocusBorder lipgloss.Border
+	// FocusMarker, if set, replaces the top-left corner of the focused
+	// box, e.g. "▸". It must be a single column wide so the layout and
+	// mouse hit-testing are unchanged.
+	FocusMarker string
+	// Title styles the field headings.
+	Title lipgloss.Style
+	// FocusedText and BlurredText style the text of the inputs.
+	FocusedText lipgloss.Style
+	BlurredText lipgloss.Style
+
+	// light, if set, replaces the theme on a terminal with a light
+	// background; see AutoTheme.
+	light *Theme
+}
+
+// NewTheme builds a theme from colors: focused and blurred for the box
+// borders, title for the field headings, and border for the box shape.
+// Without color support the focused box switches to a double border.
+func NewTheme(focused, blurred, title lipgloss.Color, border lipgloss.Border) Theme {
+	return Theme{
+		Focused:         lipgloss.NewStyle().Border(border).BorderForeground(focused),
+		Blurred:         lipgloss.NewStyle().Border(border).BorderForeground(blurred),
+		MonoFocusBorder: lipgloss.DoubleBorder(),
+		Title:           lipgloss.NewStyle().Foreground(title).Bold(true),
+		FocusedText:     lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")),
+		BlurredText:     lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")),
+	}
+}
+
+// bind resolves an AutoTheme against r and draws every style with r. The
+// focus marker is kept, as it doesn't depend on the background.
+func (t Theme) bind(r *lipgloss.Renderer) Theme {
+	if t.light != nil {
+		if r.ColorProfile() != termenv.Ascii && !r.HasDarkBackground() {
+			marker := t.FocusMarker
+			t = *t.light
+			t.FocusMarker = marker
+		}
+		t.light = nil
+	}
+	t.Focused = t.Focused.Renderer(r)
+	t.Blurred = t.Blurred.Renderer(r)
+	t.Title = t.Title.Renderer(r)
+	t.FocusedText = t.FocusedText.Renderer(r)
+	t.BlurredText = t.BlurredText.Renderer(r)
+	return t
+}
+
+// DefaultTheme returns the theme used unless another is selected.
+func DefaultTheme() Theme {
+	return NewTheme("#FFA500", "#FFFFFF", "#FFA500", lipgloss.RoundedBorder())
+}
+
+// themes are the built-in themes selectable by name.
+var themes = map[string]func() Theme{
+	"default": DefaultTheme,
+	"dark": func() Theme {
+		t := NewTheme("#7D56F4", "#444444", "#7D56F4", lipgloss.RoundedBorder())
+		t.FocusedText = t.FocusedText.Foreground(lipgloss.Color("#EEEEEE"))
+		t.BlurredText = t.BlurredText.Foreground(lipgloss.Color("#666666"))
+		return t
+	},
+	"light": func() Theme {
+		t := NewTheme("#D75F00", "#444444", "#D75F00", lipgloss.RoundedBorder())
+		t.FocusedText = t.FocusedText.Foreground(lipgloss.Color("#000000"))
+		t.BlurredText = t.BlurredText.Foreground(lipgloss.Color("#767676"))
+		return t
+	},
+	// high-contrast is meant for low-vision users: bright text on black,
+	// and a thick border on the focused box so focus reads without color.
+	"high-contrast": func() Theme {
+		return Theme{
+			Focused: lipgloss.NewStyle().
+				Border(lipgloss.ThickBorder()).
+				BorderForeground(lipgloss.Color("#FFFF00")).
+				BorderBackground(lipgloss.Color("#000000")).
+				Background(lipgloss.Color("#000000")),
+			Blurred: lipgloss.NewStyle().
+				Border(lipgloss.NormalBorder()).
+				BorderForeground(lipgloss.Color("#FFFFFF")).
+				BorderBackground(lipgloss.Color("#000000")).
+				Background(lipgloss.Color("#000000")),
+			MonoFocusBorder: lipgloss.ThickBorder(),
+			Title: lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#FFFFFF")).
+				Background(lipgloss.Color("#000000")).
+				Bold(true),
+			FocusedText: lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#FFFFFF")).
+				Background(lipgloss.Color("#000000")).
+				Bold(true),
+			BlurredText: lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#FFFFFF")).
+				Background(lipgloss.Color("#000000")),
+		}
+	},
+}
+
+// ParseTheme returns the built-in theme called name: "default", "dark",
+// "light" or "high-contrast", or "auto" to pick by the terminal's
+// background, see AutoTheme. An empty name yields the default theme.
+func ParseTheme(name string) (Theme, error) {
+	switch name {
+	case "":
+		return DefaultTheme(), nil
+	case "auto":
+		return AutoTheme(), nil
+	}
+	t, ok := themes[name]
+	if !ok {
+		return DefaultTheme(), fmt.Errorf("unknown theme %q", name)
+	}
+	return t(), nil
+}
@@ -0,0 +1,123 @@
+package cornice
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// withColorProfile makes forms built for the rest of the test detect p as
+// their terminal's color profile.
+func withColorProfile(t *testing.T, p termenv.Profile) {
+	t.Helper()
+	old := detectColorProfile
+	detectColorProfile = func(*lipgloss.Renderer) termenv.Profile { return p }
+	t.Cleanup(func() { detectColorProfile = old })
+}
+
+func TestFocusedInputUsesFocusedTextStyle(t *testing.T) {
+	m := initialModel(WithTextColors("#FF0000", "#00FF00"))
+	if got := m.styledInput(m.usernameInput, true); got.TextStyle.GetForeground() != lipgloss.Color("#FF0000") {
+		t.Errorf("focused text color = %v, want #FF0000", got.TextStyle.GetForeground())
+	}
+	if got := m.styledInput(m.usernameInput, true); got.Cursor.Style.GetForeground() != lipgloss.Color("#FF0000") {
+		t.Errorf("focused cursor color = %v, want #FF0000", got.Cursor.Style.GetForeground())
+	}
+	if got := m.styledInput(m.usernameInput, false); got.TextStyle.GetForeground() != lipgloss.Color("#00FF00") {
+		t.Errorf("blurred text color = %v, want #00FF00", got.TextStyle.GetForeground())
+	}
+}
+
+func TestThemeTextStyles(t *testing.T) {
+	theme := DefaultTheme()
+	theme.FocusedText = lipgloss.NewStyle().Bold(true)
+	theme.BlurredText = lipgloss.NewStyle().Faint(true)
+
+	m := initialModel(WithTheme(theme))
+	if !m.styledInput(m.usernameInput, true).TextStyle.GetBold() {
+		t.Error("the focused input doesn't use the theme's focused text style")
+	}
+	if !m.styledInput(m.usernameInput, false).TextStyle.GetFaint() {
+		t.Error("a blurred input doesn't use the theme's blurred text style")
+	}
+
+	// Colors given after the theme win, keeping the rest of its style.
+	m = initialModel(WithTheme(theme), WithTextColors("#FF0000", ""))
+	got := m.styledInput(m.usernameInput, true).TextStyle
+	if got.GetForeground() != lipgloss.Color("#FF0000") || !got.GetBold() {
+		t.Errorf("focused text = %v, bold %v; want #FF0000 and still bold", got.GetForeground(), got.GetBold())
+	}
+}
+
+func TestMonoProfileDistinguishesFocusByBorder(t *testing.T) {
+	withColorProfile(t, termenv.Ascii)
+	m := initialModel()
+	focused := m.boxStyle(true).GetBorderStyle()
+	blurred := m.boxStyle(false).GetBorderStyle()
+	if focused != lipgloss.DoubleBorder() {
+		t.Errorf("focused border = %+v, want the double border", focused)
+	}
+	if focused == blurred {
+		t.Error("focused and blurred boxes share a border without color")
+	}
+}
+
+func TestColorProfileKeepsThemeBorder(t *testing.T) {
+	withColorProfile(t, termenv.TrueColor)
+	m := initialModel()
+	if got := m.boxStyle(true).GetBorderStyle(); got != lipgloss.RoundedBorder() {
+		t.Errorf("focused border = %+v, want the theme's rounded border with color", got)
+	}
+}
+
+func TestHighContrastTheme(t *testing.T) {
+	theme, err := ParseTheme("high-contrast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := initialModel(WithTheme(theme))
+	if got := m.theme.Focused.GetBorderStyle(); got != lipgloss.ThickBorder() {
+		t.Errorf("focused border = %+v, want the thick border", got)
+	}
+	if !m.theme.Title.GetBold() || !m.focusedText.GetBold() {
+		t.Error("labels and focused text aren't bold")
+	}
+	if got := m.theme.Focused.GetBackground(); got != lipgloss.Color("#000000") {
+		t.Errorf("focused background = %v, want black", got)
+	}
+
+	// Without color the focused box keeps its thick border, unlike the
+	// blurred one.
+	withColorProfile(t, termenv.Ascii)
+	if m.boxStyle(true).GetBorderStyle() == m.boxStyle(false).GetBorderStyle() {
+		t.Error("focused and blurred boxes share a border without color")
+	}
+}
+
+func TestParseThemeUnknown(t *testing.T) {
+	if _, err := ParseTheme("neon"); err == nil {
+		t.Error(`ParseTheme("neon") succeeded`)
+	}
+}
+
+func TestCustomThemeColorInView(t *testing.T) {
+	withColorProfile(t, termenv.TrueColor)
+	theme := NewTheme("#123456", "#654320", "#ABCDEF", lipgloss.NormalBorder())
+	m := drive(initialModel(WithTheme(theme)), tea.WindowSizeMsg{Width: 80, Height: 24})
+	view := m.View()
+	for name, seq := range map[string]string{
+		"focused border": "\x1b[38;2;18;52;86m",
+		"blurred border": "\x1b[38;2;101;67;32m",
+		"title":          "38;2;171;205;239",
+	} {
+		if !strings.Contains(view, seq) {
+			t.Errorf("view lacks the %s color %q", name, seq)
+		}
+	}
+	if !strings.Contains(view, lipgloss.NormalBorder().TopLeft) {
+		t.Error("view doesn't use the theme's border")
+	}
+}
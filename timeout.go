@@ -0,0 +1,28 @@
+package cornice
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// idleTimeoutMsg asks the model to check whether it has been idle for
+// longer than idleTimeout.
+type idleTimeoutMsg struct{}
+
+func (m model) idleTimeoutAfter(d time.Duration) tea.Cmd {
+	return m.tick(d, func(time.Time) tea.Msg {
+		return idleTimeoutMsg{}
+	})
+}
+
+// handleIdleTimeout quits once idleTimeout has passed since the last key
+// press or mouse event, or re-arms the check for the remaining time.
+func (m model) handleIdleTimeout() (tea.Model, tea.Cmd) {
+	idle := m.now().Sub(m.lastActivity)
+	if idle >= m.idleTimeout {
+		m.timedOut = true
+		return m, tea.Quit
+	}
+	return m, m.idleTimeoutAfter(m.idleTimeout - idle)
+}
@@ -0,0 +1,43 @@
+package cornice
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestIdleTimeoutQuits(t *testing.T) {
+	clock := newFakeClock(time.Unix(0, 0))
+	m := initialModel(WithIdleTimeout(time.Minute))
+	m.now = clock.Now
+	m.lastActivity = clock.Now()
+
+	clock.Advance(time.Minute)
+	next, cmd := m.Update(idleTimeoutMsg{})
+	if !quits(cmd) {
+		t.Fatal("the idle tick didn't quit after a minute without input")
+	}
+	if !next.(model).timedOut {
+		t.Error("the quit isn't marked as a timeout")
+	}
+}
+
+func TestIdleTimeoutResetByInput(t *testing.T) {
+	clock := newFakeClock(time.Unix(0, 0))
+	m := initialModel(WithIdleTimeout(time.Minute))
+	m.now = clock.Now
+	m.lastActivity = clock.Now()
+
+	clock.Advance(40 * time.Second)
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m = next.(model)
+	clock.Advance(40 * time.Second)
+	next, cmd := m.Update(idleTimeoutMsg{})
+	if next.(model).timedOut {
+		t.Fatal("timed out 40s after a key press")
+	}
+	if cmd == nil {
+		t.Error("the check wasn't re-armed for the remaining time")
+	}
+}
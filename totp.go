@@ -0,0 +1,39 @@
+package cornice
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+const (
+	// totpDigits are the characters a one-time code may contain.
+	totpDigits = "0123456789"
+	// totpLength is the number of digits in a one-time code.
+	totpLength = 6
+)
+
+// newTOTPInput returns the input for a one-time code, capped at
+// totpLength digits.
+func newTOTPInput() textinput.Model {
+	in := textinput.New()
+	in.Placeholder = "Enter 6-digit code"
+	in.CharLimit = totpLength
+	in.Validate = func(s string) error {
+		if bad := invalidRunes(totpDigits, s); len(bad) > 0 {
+			return fmt.Errorf("%s", invalidCharsError(bad))
+		}
+		return nil
+	}
+	return in
+}
+
+// totpError checks the one-time code is exactly totpLength digits.
+func (m model) totpError() string {
+	v := m.fieldValue(fieldTOTP)
+	if utf8.RuneCountInString(v) != totpLength || len(invalidRunes(totpDigits, v)) > 0 {
+		return fmt.Sprintf("One-time code must be %d digits", totpLength)
+	}
+	return ""
+}
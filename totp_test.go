@@ -0,0 +1,67 @@
+package cornice
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// focusTOTP returns a TOTP form with the code field focused.
+func focusTOTP(t *testing.T) model {
+	t.Helper()
+	m := initialModel(WithTOTP())
+	i, ok := m.fieldIndexOf(fieldTOTP)
+	if !ok {
+		t.Fatal("no TOTP field with WithTOTP")
+	}
+	return m.setFocus(i)
+}
+
+func TestTOTPRejectsNonDigits(t *testing.T) {
+	m := drive(focusTOTP(t), keys("1a2-b3")...)
+	if v := m.totpInput.Value(); v != "123" {
+		t.Errorf("code = %q, want only the digits %q", v, "123")
+	}
+	m = drive(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x4y"), Paste: true})
+	if v := m.totpInput.Value(); v != "1234" {
+		t.Errorf("code = %q after a paste, want %q", v, "1234")
+	}
+}
+
+func TestTOTPCapsAtSixDigits(t *testing.T) {
+	m := drive(focusTOTP(t), keys("12345678")...)
+	if v := m.totpInput.Value(); v != "123456" {
+		t.Errorf("code = %q, want it capped at %q", v, "123456")
+	}
+	if err := m.totpError(); err != "" {
+		t.Errorf("six digits rejected: %s", err)
+	}
+}
+
+func TestTOTPFollowsPassword(t *testing.T) {
+	m := initialModel(WithTOTP())
+	fs := m.fields()
+	if len(fs) != 3 || fs[2] != fieldTOTP {
+		t.Fatalf("fields = %v, want the code third", fs)
+	}
+	m = drive(m, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyTab})
+	if m.focusedField() != fieldTOTP {
+		t.Errorf("two tabs focus %s, want the code", m.fieldName(m.focusedField()))
+	}
+}
+
+func TestNonInteractiveTOTP(t *testing.T) {
+	var out bytes.Buffer
+	m, err := initialModel(WithTOTP(), WithJSONOutput()).completeFromInput(strings.NewReader("alice\nsecret\n123456\n"), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := m.credentials(); c.TOTP != "123456" {
+		t.Errorf("code = %q, want %q", c.TOTP, "123456")
+	}
+	if !strings.Contains(out.String(), `"totp":"123456"`) {
+		t.Errorf("output %s lacks the code", out.String())
+	}
+}
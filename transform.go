@@ -0,0 +1,53 @@
+package cornice
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var previewStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Italic(true)
+
+// usernameTransforms are the transforms selectable for the username.
+var usernameTransforms = map[string]func(string) string{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+}
+
+// ParseTransform returns the username transform called name: "lower",
+// "upper" or "trim". An empty name yields no transform.
+func ParseTransform(name string) (func(string) string, error) {
+	if name == "" {
+		return nil, nil
+	}
+	t, ok := usernameTransforms[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown username transform %q", name)
+	}
+	return t, nil
+}
+
+// typedUsername is the username as typed, before any transform.
+func (m model) typedUsername() string {
+	return m.untab(m.usernameInput.Value())
+}
+
+// usernameValue is the username as it will be submitted.
+func (m model) usernameValue() string {
+	if m.usernameTransform == nil {
+		return m.typedUsername()
+	}
+	return m.usernameTransform(m.typedUsername())
+}
+
+// usernamePreview renders the transformed username, or "" when it matches
+// what was typed.
+func (m model) usernamePreview() string {
+	v := m.usernameValue()
+	if v == m.typedUsername() {
+		return ""
+	}
+	return m.style(previewStyle).Render("→ " + v)
+}
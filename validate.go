@@ -0,0 +1,105 @@
+package cornice
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	errorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000"))
+	invalidStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000")).Underline(true)
+
+	// missingColor marks the border of an empty field that needs a value.
+	missingColor = lipgloss.Color("#FF5F87")
+
+	// invalidColor and validColor mark the borders of fields whose value
+	// fails or passes validation, when validationBorders is set.
+	invalidColor = lipgloss.Color("#FF0000")
+	validColor   = lipgloss.Color("#00AF5F")
+)
+
+// lengthPolicy bounds the length of a field value, in runes. A zero max
+// means no upper bound.
+type lengthPolicy struct {
+	min, max int
+}
+
+// check returns an error message naming the field if value is out of
+// bounds, or "" otherwise.
+func (p lengthPolicy) check(field, value string) string {
+	n := utf8.RuneCountInString(value)
+	if n < p.min {
+		return fmt.Sprintf("%s must be at least %d characters", field, p.min)
+	}
+	if p.max > 0 && n > p.max {
+		return fmt.Sprintf("%s must be at most %d characters", field, p.max)
+	}
+	return ""
+}
+
+// allowedRune reports whether r may appear in a value restricted to charset.
+// An empty charset allows everything.
+func allowedRune(charset string, r rune) bool {
+	if r == tabRune {
+		r = '\t'
+	}
+	return charset == "" || strings.ContainsRune(charset, r)
+}
+
+// filterRunes drops the runes not in charset.
+func filterRunes(charset string, runes []rune) []rune {
+	kept := runes[:0:0]
+	for _, r := range runes {
+		if allowedRune(charset, r) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// invalidRunes returns the distinct runes of s that are not in charset, in
+// order of first appearance.
+func invalidRunes(charset, s string) []rune {
+	var bad []rune
+	for _, r := range s {
+		if !allowedRune(charset, r) && !strings.ContainsRune(string(bad), r) {
+			bad = append(bad, r)
+		}
+	}
+	return bad
+}
+
+// invalidCharsError describes the offending runes for display.
+func invalidCharsError(bad []rune) string {
+	quoted := make([]string, len(bad))
+	for i, r := range bad {
+		quoted[i] = fmt.Sprintf("%q", r)
+	}
+	return "Invalid characters: " + strings.Join(quoted, ", ")
+}
+
+// missing reports whether f should be highlighted as needing a value: it
+// is empty, empty is invalid for it, and a submit has been attempted.
+func (m model) missing(f field) bool {
+	return m.attemptedSubmit && m.fieldValue(f) == "" && m.fieldError(f) != ""
+}
+
+// validationColor returns the border color reflecting f's current value:
+// red while it fails validation and green once it passes. Untouched empty
+// fields, and every field unless validationBorders is set, keep the
+// theme's border.
+func (m model) validationColor(f field) (lipgloss.Color, bool) {
+	if !m.validationBorders || m.input(f) == nil {
+		return "", false
+	}
+	if m.fieldValue(f) == "" && !m.attemptedSubmit {
+		return "", false
+	}
+	if m.fieldError(f) != "" {
+		return invalidColor, true
+	}
+	return validColor, true
+}
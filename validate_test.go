@@ -0,0 +1,197 @@
+package cornice
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
+)
+
+const usernameCharset = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+func paste(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s), Paste: true}
+}
+
+func TestUsernameCharsFiltersTyping(t *testing.T) {
+	m := drive(initialModel(WithUsernameChars(usernameCharset, false)), keys("al!ce ")...)
+	if v := m.usernameInput.Value(); v != "alce" {
+		t.Errorf("username = %q, want the disallowed characters dropped", v)
+	}
+	if err := m.err(fieldUsername); err != "" {
+		t.Errorf("typing shows %q, want no error", err)
+	}
+}
+
+func TestUsernameCharsFlagsFilteredPaste(t *testing.T) {
+	tests := []struct {
+		name, paste, want, err string
+	}{
+		{"mixed", "al!ce", "alce", "Invalid characters: '!'"},
+		{"all invalid", "!?", "", "Invalid characters: '!', '?'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := drive(initialModel(WithUsernameChars(usernameCharset, false)), paste(tt.paste))
+			if v := m.usernameInput.Value(); v != tt.want {
+				t.Errorf("username = %q, want %q", v, tt.want)
+			}
+			if err := m.err(fieldUsername); err != tt.err {
+				t.Errorf("error = %q, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestUsernameCharsFlagsClipboardPaste(t *testing.T) {
+	m := initialModel(WithUsernameChars(usernameCharset, false))
+	m.readClipboard = func() (string, error) { return "!!\n", nil }
+	m = drive(m, tea.KeyMsg{Type: tea.KeyCtrlV})
+	if err := m.err(fieldUsername); err != "Invalid characters: '!'" {
+		t.Errorf("error = %q after pasting only invalid characters", err)
+	}
+}
+
+func TestUsernameCharsHighlightKeepsAndRejects(t *testing.T) {
+	m := drive(initialModel(WithUsernameChars(usernameCharset, true)), keys("al!ce")...)
+	if v := m.usernameInput.Value(); v != "al!ce" {
+		t.Errorf("username = %q, want the invalid character kept for highlighting", v)
+	}
+	m = drive(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.done {
+		t.Fatal("submitted with an invalid character")
+	}
+	if err := m.err(fieldUsername); err != "Invalid characters: '!'" {
+		t.Errorf("error = %q", err)
+	}
+	if m.focusedField() != fieldUsername {
+		t.Error("focus did not return to the username")
+	}
+}
+
+func TestInvalidRunes(t *testing.T) {
+	if got := string(invalidRunes("abc", "axbyxa")); got != "xy" {
+		t.Errorf("invalidRunes = %q, want each bad rune once, in order", got)
+	}
+	if got := invalidRunes("", "anything!"); len(got) != 0 {
+		t.Errorf("an empty charset rejected %q", string(got))
+	}
+}
+
+func TestLengthPolicyBoundaries(t *testing.T) {
+	tests := []struct {
+		name    string
+		opt     Option
+		f       field
+		value   string
+		wantErr string
+	}{
+		{"username below min", WithUsernameLength(3, 5), fieldUsername, "ab", "Username must be at least 3 characters"},
+		{"username at min", WithUsernameLength(3, 5), fieldUsername, "abc", ""},
+		{"username at max", WithUsernameLength(3, 5), fieldUsername, "abcde", ""},
+		{"username above max", WithUsernameLength(3, 5), fieldUsername, "abcdef", "Username must be at most 5 characters"},
+		{"password below min", WithPasswordLength(8, 12), fieldPassword, "1234567", "Password must be at least 8 characters"},
+		{"password at min", WithPasswordLength(8, 12), fieldPassword, "12345678", ""},
+		{"password at max", WithPasswordLength(8, 12), fieldPassword, "123456789012", ""},
+		{"password above max", WithPasswordLength(8, 12), fieldPassword, "1234567890123", "Password must be at most 12 characters"},
+		{"password unbounded", WithPasswordLength(1, 0), fieldPassword, "a very long password indeed", ""},
+		{"runes not bytes", WithUsernameLength(3, 3), fieldUsername, "äöü", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := initialModel(tt.opt)
+			m.input(tt.f).SetValue(tt.value)
+			if got := m.fieldError(tt.f); got != tt.wantErr {
+				t.Errorf("fieldError = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLengthPoliciesAreIndependent(t *testing.T) {
+	m := initialModel(WithUsernameLength(2, 4), WithPasswordLength(10, 0))
+	m.usernameInput.SetValue("abc")
+	m.passwordInput.SetValue("abc")
+	if err := m.fieldError(fieldUsername); err != "" {
+		t.Errorf("username error = %q, want the username policy only", err)
+	}
+	if err := m.fieldError(fieldPassword); err != "Password must be at least 10 characters" {
+		t.Errorf("password error = %q, want the password policy", err)
+	}
+}
+
+func TestMissingHighlightAppliesAndClears(t *testing.T) {
+	withColorProfile(t, termenv.TrueColor)
+	missingBorder := "38;2;255;95;135"
+
+	m := initialModel(WithUsernameLength(1, 0), WithPasswordLength(1, 0))
+	m = drive(m, tea.WindowSizeMsg{Width: 80, Height: 24})
+	if m.missing(fieldUsername) || strings.Contains(m.View(), missingBorder) {
+		t.Fatal("empty fields are highlighted before any submit")
+	}
+
+	m = drive(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.missing(fieldUsername) || !m.missing(fieldPassword) {
+		t.Fatalf("missing = %v/%v after a blocked submit, want both fields highlighted",
+			m.missing(fieldUsername), m.missing(fieldPassword))
+	}
+	if !strings.Contains(m.View(), missingBorder) {
+		t.Error("the view doesn't draw the missing highlight")
+	}
+
+	m = drive(m, keys("alice")...)
+	if m.missing(fieldUsername) {
+		t.Error("the username is still highlighted once filled")
+	}
+	if !m.missing(fieldPassword) {
+		t.Error("the empty password lost its highlight")
+	}
+	m = drive(m, script([]tea.Msg{tea.KeyMsg{Type: tea.KeyTab}}, keys("pw"))...)
+	if strings.Contains(m.View(), missingBorder) {
+		t.Error("the highlight remains with every field filled")
+	}
+}
+
+func TestUsernamePattern(t *testing.T) {
+	re := regexp.MustCompile(`^[a-z0-9_]{3,20}$`)
+	enter := []tea.Msg{tea.KeyMsg{Type: tea.KeyEnter}}
+	login := func(username string) model {
+		return drive(initialModel(WithUsernamePattern(re)), script(keys(username), []tea.Msg{tea.KeyMsg{Type: tea.KeyTab}}, keys("pw"), enter)...)
+	}
+
+	if m := login("alice_01"); !m.done || m.err(fieldUsername) != "" {
+		t.Errorf("a matching username: done = %v, error %q; want it submitted", m.done, m.err(fieldUsername))
+	}
+
+	m := login("Al")
+	want := "Username must match " + re.String()
+	if m.done || m.err(fieldUsername) != want {
+		t.Fatalf("a failing username: done = %v, error %q; want it rejected with %q", m.done, m.err(fieldUsername), want)
+	}
+	m = drive(m, keys("x")...)
+	if err := m.err(fieldUsername); err != "" {
+		t.Errorf("error = %q after editing the username, want it cleared", err)
+	}
+}
+
+func TestValidationBorders(t *testing.T) {
+	withColorProfile(t, termenv.TrueColor)
+	red, green := "38;2;255;0;0", "38;2;0;175;95"
+
+	m := initialModel(WithValidationBorders(), WithUsernameLength(3, 0))
+	m = drive(m, tea.WindowSizeMsg{Width: 80, Height: 24})
+	if v := m.View(); strings.Contains(v, red) || strings.Contains(v, green) {
+		t.Fatal("untouched empty fields are colored")
+	}
+
+	m = drive(m, keys("al")...)
+	if !strings.Contains(m.View(), red) {
+		t.Error("the view lacks the red border with a too-short username")
+	}
+	m = drive(m, keys("i")...)
+	if v := m.View(); strings.Contains(v, red) || !strings.Contains(v, green) {
+		t.Error("the border didn't turn green once the username is valid")
+	}
+}
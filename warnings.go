@@ -0,0 +1,77 @@
+package cornice
+
+import (
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	warningStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFA500"))
+	dangerStyle  = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFFFFF")).
+			Background(lipgloss.Color("#B00020")).
+			Bold(true)
+)
+
+// warnings lists the active non-blocking warnings, in a stable order.
+func (m model) warnings() []string {
+	var ws []string
+	if m.pasted {
+		ws = append(ws, "Input was pasted")
+	}
+	if m.capsLock {
+		ws = append(ws, "Caps Lock may be on")
+	}
+	if hasEdgeSpace(m.typedUsername()) || hasEdgeSpace(m.fieldValue(fieldPassword)) {
+		ws = append(ws, "Leading or trailing whitespace")
+	}
+	return ws
+}
+
+// trackCapsLock updates the Caps Lock guess from a keystroke typed into a
+// secret field. Terminals report neither Caps Lock nor Shift with printed
+// characters, so an upper-case letter is taken as a sign Caps Lock may be
+// on, and a lower-case one as a sign it's off. Other keys leave the guess
+// unchanged.
+func (m model) trackCapsLock(key tea.KeyMsg) model {
+	if key.Type != tea.KeyRunes || key.Paste || len(key.Runes) != 1 {
+		return m
+	}
+	switch r := key.Runes[0]; {
+	case unicode.IsUpper(r):
+		m.capsLock = true
+	case unicode.IsLower(r):
+		m.capsLock = false
+	}
+	return m
+}
+
+// hasEdgeSpace reports whether s starts or ends with whitespace.
+func hasEdgeSpace(s string) bool {
+	return strings.TrimFunc(s, unicode.IsSpace) != s
+}
+
+// warningLine renders all active warnings on a single line no wider than
+// the boxes. Several warnings at once switch to the danger style.
+func (m model) warningLine() string {
+	ws := m.warnings()
+	if len(ws) == 0 {
+		return ""
+	}
+	style := m.style(warningStyle)
+	if len(ws) > 1 {
+		style = m.style(dangerStyle)
+	}
+	return style.MaxWidth(m.boxWidth() + 2).Render("⚠ " + strings.Join(ws, ", "))
+}
+
+// warningHeight is the number of rows the warning line takes up.
+func (m model) warningHeight() int {
+	if len(m.warnings()) == 0 {
+		return 0
+	}
+	return 1
+}
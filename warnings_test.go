@@ -0,0 +1,66 @@
+package cornice
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestTrackCapsLock(t *testing.T) {
+	m := drive(initialModel(), script([]tea.Msg{tea.KeyMsg{Type: tea.KeyTab}}, keys("A"))...)
+	if !m.capsLock {
+		t.Error("an upper-case letter in the password didn't flag Caps Lock")
+	}
+	if m = drive(m, keys("1")...); !m.capsLock {
+		t.Error("a digit cleared the Caps Lock guess")
+	}
+	if m = drive(m, keys("a")...); m.capsLock {
+		t.Error("a lower-case letter didn't clear the Caps Lock guess")
+	}
+}
+
+func TestStackedWarningsStayOnOneLine(t *testing.T) {
+	const width, height = 40, 14
+	m := initialModel(WithColorMode(ColorNever))
+	m = drive(m, script(
+		[]tea.Msg{tea.WindowSizeMsg{Width: width, Height: height}, tea.KeyMsg{Type: tea.KeyTab}},
+		keys("A"),
+		[]tea.Msg{paste("pw ")},
+	)...)
+	if ws := m.warnings(); len(ws) != 3 {
+		t.Fatalf("warnings = %q, want paste, Caps Lock and whitespace at once", ws)
+	}
+	line := m.warningLine()
+	if h := lipgloss.Height(line); h != 1 || m.warningHeight() != 1 {
+		t.Errorf("warning line takes %d rows (warningHeight %d), want 1", h, m.warningHeight())
+	}
+	if w := lipgloss.Width(line); w > m.boxWidth()+2 {
+		t.Errorf("warning line is %d wide, wider than the %d-wide boxes", w, m.boxWidth()+2)
+	}
+	view := m.View()
+	if w, h := lipgloss.Width(view), lipgloss.Height(view); w > width || h > height {
+		t.Errorf("view is %dx%d, outside the %dx%d terminal:\n%s", w, h, width, height, view)
+	}
+}
+
+func TestCapsLockBannerToggles(t *testing.T) {
+	const banner = "Caps Lock may be on"
+	m := initialModel(WithColorMode(ColorNever))
+	m = drive(m, tea.WindowSizeMsg{Width: 80, Height: 24}, tea.KeyMsg{Type: tea.KeyTab})
+	m = drive(m, keys("S")...)
+	if !strings.Contains(m.View(), banner) {
+		t.Error("no Caps Lock banner after an upper-case password letter")
+	}
+	m = drive(m, keys("e")...)
+	if strings.Contains(m.View(), banner) {
+		t.Error("the Caps Lock banner remains after lower-case input")
+	}
+
+	// Upper case in the username is ordinary.
+	m = drive(initialModel(WithColorMode(ColorNever)), keys("Alice")...)
+	if m.capsLock {
+		t.Error("an upper-case username letter flagged Caps Lock")
+	}
+}